@@ -0,0 +1,209 @@
+package isbn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FromScannerParts builds an ISBN-13 from a scan delivered in two
+// parts: the 3 digit GS1 prefix and the rest, which is either the 9
+// body digits and the check digit, or just the 9 body digits in which
+// case the check digit is computed.
+func FromScannerParts(prefix, rest string) (*ISBN, error) {
+	if len(prefix) != 3 || !isDigits(prefix) {
+		return nil, fmt.Errorf("Scanned ISBN prefix must be 3 digits: %q", prefix)
+	}
+	if (len(rest) != 9 && len(rest) != 10) || !isDigits(rest) {
+		return nil, fmt.Errorf("Scanned ISBN must have 9 or 10 digits after the prefix: %q", rest)
+	}
+	n := &ISBN{is13: true}
+	for i := range n.prefix {
+		n.prefix[i] = prefix[i] - '0'
+	}
+	if !isAllowedPrefix(n.prefix) {
+		return nil, fmt.Errorf("Unexpected ISBN-13 prefix: %s", prefix)
+	}
+	for i := range n.digits {
+		n.digits[i] = rest[i] - '0'
+	}
+	n.checksum = check13(n.prefix, n.digits)
+	if len(rest) == 10 && rest[9]-'0' != n.checksum {
+		return nil, fmt.Errorf("ISBN checksum was incorrect")
+	}
+	return n, nil
+}
+
+// ToGTIN13 returns the ISBN-13 digits, which are also the book's
+// GTIN-13 (EAN-13)
+func (n *ISBN) ToGTIN13() string {
+	return n.to13Digits()
+}
+
+// FromGTIN13 turns a GTIN-13 into an ISBN. Any 13 digit GTIN with a
+// correct checksum is accepted, but only 978 and 979 (Bookland) GTINs
+// are books, so anything else is an error.
+func FromGTIN13(g string) (*ISBN, error) {
+	if len(g) != 13 || !isDigits(g) {
+		return nil, fmt.Errorf("GTIN-13 must be 13 digits: %q", g)
+	}
+	n := &ISBN{is13: true}
+	for i := range n.prefix {
+		n.prefix[i] = g[i] - '0'
+	}
+	for i := range n.digits {
+		n.digits[i] = g[i+3] - '0'
+	}
+	n.checksum = g[12] - '0'
+	// the ISBN-13 checksum is the GTIN-13 one, whatever the prefix
+	if !n.isValid() {
+		return nil, fmt.Errorf("GTIN-13 checksum was incorrect")
+	}
+	if !isAllowedPrefix(n.prefix) {
+		return nil, fmt.Errorf("GTIN-13 is not a book GTIN (prefix %s)", g[:3])
+	}
+	return n, nil
+}
+
+// BarcodeCaptions returns the human readable lines of an EAN-13 book
+// label: the top is `ISBN ` and the ISBN-13 hyphenated by range, and
+// the bottom is the 13 digits split as printed under the bars,
+// `9 780804 429573`. The top needs the range data, so it is an error
+// when the range is unknown.
+func (n *ISBN) BarcodeCaptions() (top string, bottom string, err error) {
+	hyphenated, err := n.To13().hyphenated()
+	if err != nil {
+		return "", "", err
+	}
+	d := n.to13Digits()
+	return "ISBN " + hyphenated, d[:1] + " " + d[1:7] + " " + d[7:], nil
+}
+
+// MatchesScan checks that a scanned barcode is this ISBN, for label
+// verification. The scan is the raw scanner output: the 13 EAN-13
+// digits, possibly with separators and followed by a 2 or 5 digit
+// add-on, which is ignored. A scan that is not an EAN-13 with a
+// correct checksum is an error; any other EAN-13 is a mismatch.
+func (n *ISBN) MatchesScan(scanned string) (bool, error) {
+	var buf [18]byte
+	digits := buf[:0]
+	for _, r := range strings.TrimSpace(scanned) {
+		switch {
+		case r >= '0' && r <= '9':
+			if len(digits) == len(buf) {
+				return false, fmt.Errorf("Scanned EAN-13 has too many digits: %q", scanned)
+			}
+			digits = append(digits, byte(r-'0'))
+		case isSeparator(r):
+		default:
+			return false, fmt.Errorf("Unexpected character in scanned EAN-13: %q", r)
+		}
+	}
+	if len(digits) != 13 && len(digits) != 13+2 && len(digits) != 13+5 {
+		return false, fmt.Errorf("Scanned EAN-13 must have 13 digits and an optional 2 or 5 digit add-on: %q", scanned)
+	}
+	sum := 0
+	for i, d := range digits[:13] {
+		sum += int(d) * ISBN13Weights[i]
+	}
+	if sum%10 != 0 {
+		return false, fmt.Errorf("Scanned EAN-13 checksum was incorrect")
+	}
+	n13 := n.To13()
+	for i, d := range n13.allDigits() {
+		if digits[i] != d {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// EAN-13 digit encodings, 7 modules each with the leftmost in the
+// highest bit. R codes are the complement of L codes and G codes are R
+// codes reversed.
+var (
+	ean13L = [10]byte{0b0001101, 0b0011001, 0b0010011, 0b0111101, 0b0100011, 0b0110001, 0b0101111, 0b0111011, 0b0110111, 0b0001011}
+	ean13G = [10]byte{0b0100111, 0b0110011, 0b0011011, 0b0100001, 0b0011101, 0b0111001, 0b0000101, 0b0010001, 0b0001001, 0b0010111}
+	ean13R = [10]byte{0b1110010, 0b1100110, 0b1101100, 0b1000010, 0b1011100, 0b1001110, 0b1010000, 0b1000100, 0b1001000, 0b1110100}
+)
+
+// ean13Parity gives, by the first digit, which of the six left hand
+// digits use G codes rather than L codes, leftmost in the highest bit
+var ean13Parity = [10]byte{0b000000, 0b001011, 0b001101, 0b001110, 0b010011, 0b011001, 0b011100, 0b010101, 0b010110, 0b011010}
+
+// ean13Modules is the number of modules in an EAN-13 barcode: two
+// 3 module guards, a 5 module centre guard and twelve 7 module digits
+const ean13Modules = 3 + 6*7 + 5 + 6*7 + 3
+
+// EAN13Pattern returns the 95 modules of the ISBN-13's EAN-13 barcode,
+// left to right, true for a bar. The first digit is encoded in the
+// parity of the left hand digits. It is an error if the ISBN is not a
+// valid value.
+func (n *ISBN) EAN13Pattern() ([]bool, error) {
+	return n.appendEAN13Pattern(make([]bool, 0, ean13Modules))
+}
+
+// EAN13Patterns is EAN13Pattern for a batch, with the patterns sharing
+// one allocation. The results are index aligned with in; a nil or
+// invalid ISBN gets a nil pattern and an error at its index.
+func EAN13Patterns(in []*ISBN) ([][]bool, []error) {
+	patterns := make([][]bool, len(in))
+	errs := make([]error, len(in))
+	buf := make([]bool, 0, len(in)*ean13Modules)
+	for i, n := range in {
+		if n == nil {
+			errs[i] = fmt.Errorf("ISBN %d is nil", i)
+			continue
+		}
+		p, err := n.appendEAN13Pattern(buf[len(buf):])
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		patterns[i] = p[:ean13Modules:ean13Modules]
+		buf = buf[:len(buf)+ean13Modules]
+	}
+	return patterns, errs
+}
+
+// appendEAN13Pattern appends the EAN13Pattern modules to dst, leaving
+// it untouched on error
+func (n *ISBN) appendEAN13Pattern(dst []bool) ([]bool, error) {
+	n13, err := n.To13Checked()
+	if err != nil {
+		return nil, err
+	}
+	first := n13.prefix[0]
+	left := [6]byte{n13.prefix[1], n13.prefix[2], n13.digits[0], n13.digits[1], n13.digits[2], n13.digits[3]}
+	right := [6]byte{n13.digits[4], n13.digits[5], n13.digits[6], n13.digits[7], n13.digits[8], n13.checksum}
+	dst = append(dst, true, false, true)
+	for i, d := range left {
+		code := ean13L[d]
+		if ean13Parity[first]&(1<<uint(5-i)) != 0 {
+			code = ean13G[d]
+		}
+		dst = appendModules(dst, code)
+	}
+	dst = append(dst, false, true, false, true, false)
+	for _, d := range right {
+		dst = appendModules(dst, ean13R[d])
+	}
+	return append(dst, true, false, true), nil
+}
+
+// appendModules appends the 7 modules of a digit code
+func appendModules(dst []bool, code byte) []bool {
+	for bit := 6; bit >= 0; bit-- {
+		dst = append(dst, code&(1<<uint(bit)) != 0)
+	}
+	return dst
+}
+
+// isDigits checks that s is only ASCII digits
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,238 @@
+package isbn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFromScannerParts(t *testing.T) {
+	cases := []struct {
+		prefix, rest string
+		isbn         string
+		valid        bool
+	}{
+		{"978", "0804429573", "978-0804429573", true},
+		{"978", "080442957", "978-0804429573", true},
+		{"979", "5000000235", "979-5000000235", true},
+		{"979", "500000023", "979-5000000235", true},
+		// bad checksum
+		{"978", "0804429574", "", false},
+		// not a book prefix
+		{"977", "080442957", "", false},
+		// lengths and characters
+		{"97", "80804429573", "", false},
+		{"978", "08044295", "", false},
+		{"978", "08044295731", "", false},
+		{"978", "080442957X", "", false},
+		{"978", "0-8044-2957", "", false},
+	}
+	for _, c := range cases {
+		n, err := FromScannerParts(c.prefix, c.rest)
+		if !c.valid {
+			if err == nil {
+				t.Errorf("Expected FromScannerParts(%s, %s) to fail, got %s", c.prefix, c.rest, n)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Failed FromScannerParts(%s, %s), error: %s", c.prefix, c.rest, err)
+			continue
+		}
+		checkStringEqual(t, "Scanned ISBN", n.String(), c.isbn)
+	}
+}
+
+func TestGTIN13(t *testing.T) {
+	n, err := Parse("080442957X")
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	checkStringEqual(t, "GTIN-13 of an ISBN-10", n.ToGTIN13(), "9780804429573")
+	cases := []struct {
+		gtin  string
+		isbn  string
+		valid bool
+	}{
+		{"9780804429573", "978-0804429573", true},
+		{"9795000000235", "979-5000000235", true},
+		// bad checksum
+		{"9780804429574", "", false},
+		// a valid GTIN, but not a book (Nutella)
+		{"3017620422003", "", false},
+		// wrong lengths and characters
+		{"978080442957", "", false},
+		{"978-0804429573", "", false},
+		{"080442957X", "", false},
+	}
+	for _, c := range cases {
+		n, err := FromGTIN13(c.gtin)
+		if !c.valid {
+			if err == nil {
+				t.Errorf("Expected FromGTIN13(%s) to fail, got %s", c.gtin, n)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Failed FromGTIN13(%s), error: %s", c.gtin, err)
+			continue
+		}
+		checkStringEqual(t, "ISBN from GTIN-13", n.String(), c.isbn)
+		checkStringEqual(t, "GTIN-13 round trip", n.ToGTIN13(), c.gtin)
+	}
+	if _, err := FromGTIN13("3017620422003"); err == nil || !strings.Contains(err.Error(), "not a book") {
+		t.Errorf("Expected a non-book GTIN error, got %v", err)
+	}
+}
+
+func TestBarcodeCaptions(t *testing.T) {
+	for _, s := range []string{"080442957X", "9780804429573"} {
+		n, err := Parse(s)
+		if err != nil {
+			t.Fatalf("Failed to parse `%s`, error: %s", s, err)
+		}
+		top, bottom, err := n.BarcodeCaptions()
+		if err != nil {
+			t.Errorf("Failed BarcodeCaptions of `%s`, error: %s", s, err)
+			continue
+		}
+		checkStringEqual(t, "Top caption", top, "ISBN 978-0-8044-2957-3")
+		checkStringEqual(t, "Bottom caption", bottom, "9 780804 429573")
+	}
+	n, err := Parse(test979isbn)
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	if _, _, err := n.BarcodeCaptions(); err == nil {
+		t.Errorf("Expected BarcodeCaptions to fail for an unknown range")
+	}
+}
+
+func patternString(p []bool) string {
+	var b strings.Builder
+	for _, bar := range p {
+		if bar {
+			b.WriteByte('1')
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	return b.String()
+}
+
+func TestEAN13Pattern(t *testing.T) {
+	expected := "10101110110001001010011101101110100111010001101010101110011011001110100100111010001001000010101"
+	for _, s := range []string{"080442957X", "9780804429573"} {
+		n, err := Parse(s)
+		if err != nil {
+			t.Fatalf("Failed to parse `%s`, error: %s", s, err)
+		}
+		p, err := n.EAN13Pattern()
+		if err != nil {
+			t.Fatalf("Failed EAN13Pattern of %s, error: %s", s, err)
+		}
+		checkStringEqual(t, "EAN-13 pattern", patternString(p), expected)
+	}
+	bad := &ISBN{is13: true, prefix: [3]byte{9, 7, 8}, digits: [9]byte{12}}
+	if _, err := bad.EAN13Pattern(); err == nil {
+		t.Errorf("Expected an error for an invalid ISBN value")
+	}
+}
+
+func TestEAN13Patterns(t *testing.T) {
+	a, _ := Parse("080442957X")
+	b, _ := Parse("9780836220889")
+	bad := &ISBN{is13: true, prefix: [3]byte{9, 7, 8}, digits: [9]byte{12}}
+	patterns, errs := EAN13Patterns([]*ISBN{a, nil, bad, b})
+	if len(patterns) != 4 || len(errs) != 4 {
+		t.Fatalf("Expected 4 results, got %d patterns and %d errors", len(patterns), len(errs))
+	}
+	for i, n := range []*ISBN{a, b} {
+		j := i * 3
+		want, _ := n.EAN13Pattern()
+		if errs[j] != nil {
+			t.Errorf("Unexpected error at %d: %s", j, errs[j])
+		}
+		checkStringEqual(t, "Batch EAN-13 pattern", patternString(patterns[j]), patternString(want))
+	}
+	for _, j := range []int{1, 2} {
+		if patterns[j] != nil || errs[j] == nil {
+			t.Errorf("Expected a nil pattern and an error at %d", j)
+		}
+	}
+	// appending to one pattern must not overwrite the next
+	last := patternString(patterns[3])
+	_ = append(patterns[0], true)
+	checkStringEqual(t, "Batch EAN-13 pattern after append", patternString(patterns[3]), last)
+}
+
+func benchmarkISBNs() []*ISBN {
+	in := make([]*ISBN, 100)
+	for i := range in {
+		in[i], _ = Parse("9780804429573")
+	}
+	return in
+}
+
+func BenchmarkEAN13Pattern(b *testing.B) {
+	in := benchmarkISBNs()
+	out := make([][]bool, len(in))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for j, n := range in {
+			out[j], _ = n.EAN13Pattern()
+		}
+	}
+}
+
+func BenchmarkEAN13Patterns(b *testing.B) {
+	in := benchmarkISBNs()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		EAN13Patterns(in)
+	}
+}
+
+func TestMatchesScan(t *testing.T) {
+	n, err := Parse("080442957X")
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	cases := []struct {
+		scanned string
+		matches bool
+		valid   bool
+	}{
+		{"9780804429573", true, true},
+		{" 9780804429573\r\n", true, true},
+		{"978 0804429573", true, true},
+		// add-ons, e.g. a price
+		{"978080442957351599", true, true},
+		{"978080442957312", true, true},
+		{"9780804429573 51599", true, true},
+		// mismatches
+		{"9780836220889", false, true},
+		{"978083622088951599", false, true},
+		{"4006381333931", false, true},
+		// invalid scans
+		{"9780804429574", false, false},
+		{"978080442957", false, false},
+		{"97808044295731", false, false},
+		{"9780804429573515991", false, false},
+		{"080442957X", false, false},
+		{"", false, false},
+	}
+	for _, c := range cases {
+		matches, err := n.MatchesScan(c.scanned)
+		if !c.valid {
+			if err == nil {
+				t.Errorf("Expected scan %q to be invalid", c.scanned)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Failed to match scan %q, error: %s", c.scanned, err)
+		} else if matches != c.matches {
+			t.Errorf("Expected scan %q to match %s: %t", c.scanned, n, c.matches)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package isbn
+
+// CanonicalizeColumn parses a column of ISBN strings in one pass,
+// returning index aligned columns of the canonical forms and whether
+// each input was valid. Invalid inputs get an empty canonical form.
+// Parsing and formatting reuse the same buffers for every row.
+func CanonicalizeColumn(in []string) (out []string, valid []bool) {
+	out = make([]string, len(in))
+	valid = make([]bool, len(in))
+	var n ISBN
+	buf := make([]byte, 0, len(urnPrefix)+14)
+	for i, s := range in {
+		if parseInto(s, &n) != nil {
+			continue
+		}
+		buf = n.appendCanonical(buf[:0])
+		out[i] = string(buf)
+		valid[i] = true
+	}
+	return out, valid
+}
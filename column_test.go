@@ -0,0 +1,44 @@
+package isbn
+
+import (
+	"testing"
+)
+
+func TestCanonicalizeColumn(t *testing.T) {
+	var in []string
+	for _, v := range tests {
+		in = append(in, v.isbn10, v.isbn13)
+	}
+	out, valid := CanonicalizeColumn(in)
+	if len(out) != len(in) || len(valid) != len(in) {
+		t.Fatalf("Expected %d outputs, got %d and %d", len(in), len(out), len(valid))
+	}
+	for i, s := range in {
+		n, err := Parse(s)
+		if valid[i] != (err == nil) {
+			t.Errorf("Column validity of `%s` was %v, expected %v", s, valid[i], err == nil)
+			continue
+		}
+		expected := ""
+		if err == nil {
+			expected = n.Canonical()
+		}
+		checkStringEqual(t, "Column canonical form of "+s, out[i], expected)
+	}
+}
+
+func BenchmarkCanonicalizeColumn(b *testing.B) {
+	in := make([]string, 100000)
+	for i := range in {
+		v := tests[(i/2)%len(tests)]
+		in[i] = v.isbn10
+		if i%2 == 1 {
+			in[i] = v.isbn13
+		}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CanonicalizeColumn(in)
+	}
+}
@@ -0,0 +1,165 @@
+package isbn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EditKind is the kind of change MinimalFix made
+type EditKind int
+
+const (
+	// Transposition swapped the digits at Pos and Pos+1
+	Transposition EditKind = iota + 1
+	// Substitution replaced the digit at Pos with To
+	Substitution
+)
+
+// Edit describes a single change to the digits of an ISBN. Positions
+// count the digits only (separators and urn prefix are ignored) from 0,
+// and digits are values, so X is 10. For a Transposition, From and To
+// are the digits at Pos and Pos+1 before the swap.
+type Edit struct {
+	Kind     EditKind
+	Pos      int
+	From, To byte
+}
+
+// String describes the edit for the user
+func (e Edit) String() string {
+	switch e.Kind {
+	case Transposition:
+		return fmt.Sprintf("swapped digits %d and %d", e.Pos+1, e.Pos+2)
+	case Substitution:
+		return fmt.Sprintf("changed digit %d from %c to %c", e.Pos+1, isbnDigitToByte(e.From), isbnDigitToByte(e.To))
+	default:
+		return "no change"
+	}
+}
+
+// MinimalFix finds the single smallest change that makes s a valid
+// ISBN, for inputs that have the right format but fail the checksum.
+// The edits tried, from cheapest, are:
+//
+//  1. swapping two adjacent digits, the most common keying error,
+//     leftmost first
+//  2. changing the check digit, which keeps all the other digits
+//  3. changing one other digit, leftmost and then lowest first
+//
+// and the first that gives a valid ISBN is returned, so the choice is
+// deterministic.
+func MinimalFix(s string) (*ISBN, Edit, error) {
+	n := &ISBN{}
+	if err := parseDigits(s, n); err != nil {
+		return nil, Edit{}, err
+	}
+	if n.isValid() {
+		return nil, Edit{}, fmt.Errorf("ISBN is already valid")
+	}
+	digits := n.allDigits()
+	for i := 0; i+1 < len(digits); i++ {
+		if digits[i] == digits[i+1] {
+			continue
+		}
+		fixed := append([]byte(nil), digits...)
+		fixed[i], fixed[i+1] = fixed[i+1], fixed[i]
+		if m, ok := fromAllDigits(fixed); ok {
+			return m, Edit{Kind: Transposition, Pos: i, From: digits[i], To: digits[i+1]}, nil
+		}
+	}
+	// the check digit first, then the rest
+	last := len(digits) - 1
+	if m, edit, ok := substitute(digits, last); ok {
+		return m, edit, nil
+	}
+	for i := 0; i < last; i++ {
+		if m, edit, ok := substitute(digits, i); ok {
+			return m, edit, nil
+		}
+	}
+	return nil, Edit{}, fmt.Errorf("No single edit fixes the ISBN")
+}
+
+// substitute tries each replacement for the digit at i, lowest first
+func substitute(digits []byte, i int) (*ISBN, Edit, bool) {
+	for d := byte(0); d <= 10; d++ {
+		if d == digits[i] {
+			continue
+		}
+		fixed := append([]byte(nil), digits...)
+		fixed[i] = d
+		if m, ok := fromAllDigits(fixed); ok {
+			return m, Edit{Kind: Substitution, Pos: i, From: digits[i], To: d}, true
+		}
+	}
+	return nil, Edit{}, false
+}
+
+// CompleteAmbiguousCheck completes an ISBN whose check digit could not
+// be read, from the body: 9 digits for an ISBN-10 (whose check may turn
+// out to be X) or 12 for an ISBN-13, which must have a book prefix.
+// Separators are ignored as in Parse.
+func CompleteAmbiguousCheck(body string) (*ISBN, error) {
+	if len(body) > maxInputLen {
+		return nil, fmt.Errorf("Invalid ISBN format")
+	}
+	var digits []byte
+	for _, r := range strings.TrimPrefix(body, urnPrefix) {
+		switch d := runeToISBNDigit(r); d {
+		case -1:
+		case 10:
+			return nil, fmt.Errorf("Unexpected character in ISBN body (X can only be the final digit of an ISBN-10)")
+		default:
+			digits = append(digits, byte(d))
+		}
+	}
+	n := &ISBN{is13: len(digits) == 12}
+	switch len(digits) {
+	case 9:
+		copy(n.digits[:], digits)
+		n.checksum = check10(n.digits)
+	case 12:
+		copy(n.prefix[:], digits)
+		if !isAllowedPrefix(n.prefix) {
+			return nil, fmt.Errorf("Unexpected ISBN-13 prefix: %s", digitString(n.prefix[:]))
+		}
+		copy(n.digits[:], digits[3:])
+		n.checksum = check13(n.prefix, n.digits)
+	default:
+		return nil, fmt.Errorf("ISBN body must have 9 or 12 digits, not %d", len(digits))
+	}
+	return n, nil
+}
+
+// allDigits returns all the digit values of the ISBN in order, check
+// digit included
+func (n *ISBN) allDigits() []byte {
+	var all []byte
+	if n.is13 {
+		all = append(all, n.prefix[:]...)
+	}
+	all = append(all, n.digits[:]...)
+	return append(all, n.checksum)
+}
+
+// fromAllDigits is the inverse of allDigits, and reports whether the
+// digits are a valid ISBN
+func fromAllDigits(all []byte) (*ISBN, bool) {
+	n := &ISBN{is13: len(all) == 13}
+	if n.is13 {
+		copy(n.prefix[:], all)
+		all = all[3:]
+		if !isAllowedPrefix(n.prefix) {
+			return nil, false
+		}
+	}
+	copy(n.digits[:], all)
+	n.checksum = all[9]
+	for i, d := range all {
+		// X is only allowed as an ISBN-10 check digit
+		if d == 10 && (n.is13 || i != 9) {
+			return nil, false
+		}
+	}
+	return n, n.isValid()
+}
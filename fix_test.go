@@ -0,0 +1,84 @@
+package isbn
+
+import (
+	"testing"
+)
+
+func TestMinimalFix(t *testing.T) {
+	cases := []struct {
+		input string
+		isbn  string
+		edit  Edit
+	}{
+		// swapped digits
+		{"080424957X", "080442957X", Edit{Transposition, 4, 2, 4}},
+		{"978-0804492573", "978-0804429573", Edit{Transposition, 8, 9, 2}},
+		// a mistyped check digit
+		{"0836220888", "0836220889", Edit{Substitution, 9, 8, 9}},
+		{"080442957-0", "080442957X", Edit{Substitution, 9, 0, 10}},
+		{"978-0-8044-2957-4", "978-0804429573", Edit{Substitution, 12, 4, 3}},
+		// the wrong length can't be fixed
+		{"08044295", "", Edit{}},
+	}
+	for _, c := range cases {
+		n, edit, err := MinimalFix(c.input)
+		if c.isbn == "" {
+			if err == nil {
+				t.Errorf("Expected MinimalFix(%s) to fail, got %s (%s)", c.input, n, edit)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Failed MinimalFix(%s), error: %s", c.input, err)
+			continue
+		}
+		checkStringEqual(t, "Fixed ISBN", n.String(), c.isbn)
+		if edit != c.edit {
+			t.Errorf("MinimalFix(%s) edit was %+v (%s), expected %+v", c.input, edit, edit, c.edit)
+		}
+	}
+	// valid ISBNs need no fix
+	if _, _, err := MinimalFix("080442957X"); err == nil {
+		t.Errorf("Expected MinimalFix of a valid ISBN to fail")
+	}
+}
+
+func TestEditString(t *testing.T) {
+	checkStringEqual(t, "Transposition", Edit{Transposition, 4, 4, 2}.String(), "swapped digits 5 and 6")
+	checkStringEqual(t, "Substitution", Edit{Substitution, 9, 1, 10}.String(), "changed digit 10 from 1 to X")
+}
+
+func TestCompleteAmbiguousCheck(t *testing.T) {
+	cases := []struct {
+		body  string
+		isbn  string
+		valid bool
+	}{
+		{"080442957", "080442957X", true},
+		{"0-8044-2957", "080442957X", true},
+		{"083622088", "0836220889", true},
+		{"978080442957", "978-0804429573", true},
+		{"978-0-8044-2957", "978-0804429573", true},
+		{"979500000023", "979-5000000235", true},
+		// a complete ISBN is not a body
+		{"080442957X", "", false},
+		{"9780804429573", "", false},
+		{"08044295", "", false},
+		{"977080442957", "", false},
+		{"08044295X", "", false},
+	}
+	for _, c := range cases {
+		n, err := CompleteAmbiguousCheck(c.body)
+		if !c.valid {
+			if err == nil {
+				t.Errorf("Expected CompleteAmbiguousCheck(%s) to fail, got %s", c.body, n)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Failed CompleteAmbiguousCheck(%s), error: %s", c.body, err)
+			continue
+		}
+		checkStringEqual(t, "Completed ISBN", n.String(), c.isbn)
+	}
+}
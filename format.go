@@ -0,0 +1,83 @@
+package isbn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatProfile formats the ISBN according to a named profile, so the
+// output format can come from configuration. The profiles are:
+//
+//	bare13    the ISBN-13 digits, `9780804429573`
+//	hyphen13  the ISBN-13 hyphenated by range, `978-0-8044-2957-3`
+//	urn       the ISBN-13 urn, `urn:isbn:978-0804429573`
+//	hyphen10  the ISBN-10 hyphenated by range, `0-8044-2957-X`
+//	gtin14    the GTIN-14, `09780804429573`
+//
+// Unknown profiles are an error, as are the hyphenated profiles when
+// the range is unknown, or hyphen10 for a 979 ISBN.
+func (n *ISBN) FormatProfile(name string) (string, error) {
+	switch name {
+	case "bare13":
+		return n.to13Digits(), nil
+	case "hyphen13":
+		return n.To13().hyphenated()
+	case "urn":
+		return n.To13().ToURN(), nil
+	case "hyphen10":
+		return n.To10().hyphenated()
+	case "gtin14":
+		return "0" + n.to13Digits(), nil
+	default:
+		return "", fmt.Errorf("Unknown ISBN format profile: %s", name)
+	}
+}
+
+// SpokenForm renders the ISBN for screen readers: single digits
+// separated by spaces, with a comma (a pause) between the range
+// segments, e.g. `0, 8 0 4 4, 2 9 5 7, X`. When the range is unknown
+// the groups are the prefix, the body and the check digit instead.
+// The ISBN-10 form of a 979 ISBN has no spoken form.
+func (n *ISBN) SpokenForm() (string, error) {
+	if n.is979Form10() {
+		return "", fmt.Errorf("ISBN-10 form of a %s prefixed ISBN has no spoken form", digitString(n.prefix[:]))
+	}
+	var groups []string
+	if seg, err := n.segments(); err == nil {
+		groups = []string{seg.prefix, seg.group, seg.registrant, seg.publication, seg.check}
+	} else {
+		groups = []string{digitString(n.digits[:]), digitString([]byte{n.checksum})}
+		if n.is13 {
+			groups = append([]string{digitString(n.prefix[:])}, groups...)
+		}
+	}
+	spoken := make([]string, 0, len(groups))
+	for _, g := range groups {
+		if g != "" {
+			spoken = append(spoken, strings.Join(strings.Split(g, ""), " "))
+		}
+	}
+	return strings.Join(spoken, ", "), nil
+}
+
+// BibTeX returns the ISBN as it should appear in a BibTeX
+// `isbn = {...}` field: the ISBN-13 hyphenated by range, or just its
+// digits when the range is unknown.
+func (n *ISBN) BibTeX() string {
+	if s, err := n.To13().hyphenated(); err == nil {
+		return s
+	}
+	return n.to13Digits()
+}
+
+// ShortLabel abbreviates the ISBN, in its own form, for narrow
+// columns: always the first 3 digits, an ellipsis, the last 4 digits
+// before the check digit, a hyphen and the check digit, e.g.
+// `978…2957-3` or `080…2957-X`. It does not use the range data, so it
+// is the same for every ISBN of a form.
+func (n *ISBN) ShortLabel() string {
+	all := n.allDigits()
+	label := append([]byte(digitString(all[:3])), "…"...)
+	label = append(label, digitString(all[len(all)-5:len(all)-1])...)
+	return string(append(label, '-', isbnDigitToByte(n.checksum)))
+}
@@ -0,0 +1,109 @@
+package isbn
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFormatProfile(t *testing.T) {
+	cases := []struct {
+		isbn     string
+		profile  string
+		expected string
+		valid    bool
+	}{
+		{"080442957X", "bare13", "9780804429573", true},
+		{"080442957X", "hyphen13", "978-0-8044-2957-3", true},
+		{"080442957X", "urn", "urn:isbn:978-0804429573", true},
+		{"9780804429573", "hyphen10", "0-8044-2957-X", true},
+		{"080442957X", "gtin14", "09780804429573", true},
+		{test979isbn, "bare13", "9795000000235", true},
+		{test979isbn, "gtin14", "09795000000235", true},
+		// 979 has no ISBN-10
+		{"979-10-90636-07-1", "hyphen13", "979-10-90636-07-1", true},
+		{"979-10-90636-07-1", "hyphen10", "", false},
+		// 979-5 is not in the range data
+		{test979isbn, "hyphen13", "", false},
+		{"080442957X", "nope", "", false},
+	}
+	for _, c := range cases {
+		n, err := Parse(c.isbn)
+		if err != nil {
+			t.Errorf("Failed to parse `%s`, error: %s", c.isbn, err)
+			continue
+		}
+		s, err := n.FormatProfile(c.profile)
+		if !c.valid {
+			if err == nil {
+				t.Errorf("Expected profile %s to fail for `%s`, got `%s`", c.profile, c.isbn, s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Failed to format `%s` as %s, error: %s", c.isbn, c.profile, err)
+			continue
+		}
+		checkStringEqual(t, "Formatted profile "+c.profile, s, c.expected)
+	}
+}
+
+func TestSpokenForm(t *testing.T) {
+	cases := []struct {
+		isbn   string
+		spoken string
+	}{
+		{"080442957X", "0, 8 0 4 4, 2 9 5 7, X"},
+		{"9780804429573", "9 7 8, 0, 8 0 4 4, 2 9 5 7, 3"},
+		// unknown range
+		{test979isbn, "9 7 9, 5 0 0 0 0 0 0 2 3, 5"},
+	}
+	for _, c := range cases {
+		n, err := Parse(c.isbn)
+		if err != nil {
+			t.Errorf("Failed to parse `%s`, error: %s", c.isbn, err)
+			continue
+		}
+		s, err := n.SpokenForm()
+		if err != nil {
+			t.Errorf("Failed to render `%s`, error: %s", c.isbn, err)
+			continue
+		}
+		checkStringEqual(t, "Spoken form", s, c.spoken)
+	}
+	n, err := Parse(test979isbn)
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	if s, err := n.To10().SpokenForm(); err == nil {
+		t.Errorf("Expected the ISBN-10 form of a 979 to fail, got `%s`", s)
+	}
+}
+
+func TestBibTeX(t *testing.T) {
+	n, err := Parse(test979isbn)
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	checkStringEqual(t, "BibTeX falls back to the digits", n.BibTeX(), "9795000000235")
+}
+
+func ExampleISBN_BibTeX() {
+	n, _ := Parse("080442957X")
+	fmt.Printf("isbn = {%s}\n", n.BibTeX())
+	// Output: isbn = {978-0-8044-2957-3}
+}
+
+func TestShortLabel(t *testing.T) {
+	for s, label := range map[string]string{
+		"080442957X":             "080…2957-X",
+		"9780804429573":          "978…2957-3",
+		"urn:isbn:0-8362-2088-9": "083…2088-9",
+		test979isbn:              "979…0023-5",
+	} {
+		n, err := Parse(s)
+		if err != nil {
+			t.Fatalf("Failed to parse `%s`, error: %s", s, err)
+		}
+		checkStringEqual(t, "Short label of "+s, n.ShortLabel(), label)
+	}
+}
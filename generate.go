@@ -0,0 +1,43 @@
+package isbn
+
+import (
+	"math/rand"
+)
+
+// GenerateSequence returns n distinct, valid ISBNs generated from seed,
+// for use as test fixtures. The same seed, n and is13 always give the
+// same sequence (math/rand sources are stable for a given seed), and a
+// longer sequence starts with the shorter one. ISBN-13s use both the
+// 978 and 979 prefixes. It panics if n is more than the number of
+// distinct ISBNs of the requested form.
+func GenerateSequence(seed int64, n int, is13 bool) []*ISBN {
+	const bodies = 1000000000
+	if n <= 0 {
+		return nil
+	}
+	if is13 && n > bodies*len(allowedISBN13Prefixes) || !is13 && n > bodies {
+		panic("Too many ISBNs requested")
+	}
+	r := rand.New(rand.NewSource(seed))
+	seen := make(map[uint64]bool, n)
+	seq := make([]*ISBN, 0, n)
+	for len(seq) < n {
+		g := &ISBN{is13: is13}
+		body := r.Intn(bodies)
+		for i := len(g.digits) - 1; i >= 0; i-- {
+			g.digits[i] = byte(body % 10)
+			body /= 10
+		}
+		if is13 {
+			copy(g.prefix[:], allowedISBN13Prefixes[r.Intn(len(allowedISBN13Prefixes))])
+			g.checksum = check13(g.prefix, g.digits)
+		} else {
+			g.checksum = check10(g.digits)
+		}
+		if key := g.Pack(); !seen[key] {
+			seen[key] = true
+			seq = append(seq, g)
+		}
+	}
+	return seq
+}
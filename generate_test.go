@@ -0,0 +1,46 @@
+package isbn
+
+import (
+	"testing"
+)
+
+func TestGenerateSequence(t *testing.T) {
+	for _, is13 := range []bool{false, true} {
+		a := GenerateSequence(42, 1000, is13)
+		b := GenerateSequence(42, 1000, is13)
+		if len(a) != 1000 || len(b) != 1000 {
+			t.Fatalf("Expected 1000 ISBNs, got %d and %d", len(a), len(b))
+		}
+		seen := map[string]bool{}
+		for i, n := range a {
+			if *n != *b[i] {
+				t.Errorf("Sequences from the same seed differ at %d: %s vs %s", i, n, b[i])
+			}
+			if n.Is13() != is13 {
+				t.Errorf("Generated ISBN %s is the wrong form", n)
+			}
+			if _, err := Parse(n.String()); err != nil {
+				t.Errorf("Generated ISBN %s is invalid: %s", n, err)
+			}
+			if seen[n.String()] {
+				t.Errorf("Generated ISBN %s is a duplicate", n)
+			}
+			seen[n.String()] = true
+		}
+		other := GenerateSequence(43, 1000, is13)
+		if *other[0] == *a[0] && *other[1] == *a[1] {
+			t.Errorf("Expected different seeds to give different sequences")
+		}
+		short := GenerateSequence(42, 10, is13)
+		for i, n := range short {
+			if *n != *a[i] {
+				t.Errorf("Expected a shorter sequence to be a prefix of the longer one")
+			}
+		}
+	}
+	for _, n := range []int{0, -1} {
+		if s := GenerateSequence(42, n, true); len(s) != 0 {
+			t.Errorf("Expected an empty sequence for %d, got %d", n, len(s))
+		}
+	}
+}
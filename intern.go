@@ -0,0 +1,43 @@
+package isbn
+
+import (
+	"sync"
+)
+
+// Interner parses ISBNs, returning the same *ISBN for every input
+// that is the same book, to save memory on data with many repeats.
+// The shared value is the ISBN-13 form, as the ISBNs returned by this
+// package are never modified. It is safe for concurrent use.
+type Interner struct {
+	mu   sync.Mutex
+	pool map[string]*ISBN
+}
+
+// NewInterner creates an empty Interner
+func NewInterner() *Interner {
+	return &Interner{pool: map[string]*ISBN{}}
+}
+
+// Intern parses s and returns the shared ISBN for it
+func (in *Interner) Intern(s string) (*ISBN, error) {
+	n, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	n = n.To13()
+	key := n.String()
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if shared, ok := in.pool[key]; ok {
+		return shared, nil
+	}
+	in.pool[key] = n
+	return n, nil
+}
+
+// Len returns the number of distinct ISBNs interned
+func (in *Interner) Len() int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return len(in.pool)
+}
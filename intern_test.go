@@ -0,0 +1,57 @@
+package isbn
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestInterner(t *testing.T) {
+	in := NewInterner()
+	var shared *ISBN
+	for _, s := range []string{"080442957X", "9780804429573", "urn:isbn:978-0-8044-2957-3", "0-8044-2957-x"} {
+		n, err := in.Intern(s)
+		if err != nil {
+			t.Errorf("Failed to intern `%s`, error: %s", s, err)
+			continue
+		}
+		if shared == nil {
+			shared = n
+		}
+		if n != shared {
+			t.Errorf("Expected `%s` to intern to the same pointer", s)
+		}
+	}
+	checkStringEqual(t, "Interned form should be ISBN-13", shared.String(), "978-0804429573")
+	other, err := in.Intern("0836220889")
+	if err != nil {
+		t.Fatalf("Failed to intern: %s", err)
+	}
+	if other == shared {
+		t.Errorf("Expected different books not to share a pointer")
+	}
+	if _, err := in.Intern("badformat!"); err == nil {
+		t.Errorf("Expected an invalid ISBN not to intern")
+	}
+	if in.Len() != 2 {
+		t.Errorf("Expected 2 interned ISBNs, got %d", in.Len())
+	}
+}
+
+func TestInternerConcurrent(t *testing.T) {
+	in := NewInterner()
+	results := make([]*ISBN, 16)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = in.Intern(tests[i%2].isbn10)
+		}(i)
+	}
+	wg.Wait()
+	for i, n := range results {
+		if n != results[i%2] {
+			t.Errorf("Expected concurrent interning to share pointers")
+		}
+	}
+}
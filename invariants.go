@@ -0,0 +1,101 @@
+package isbn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AssertInvariants checks the guarantees this package makes about an
+// ISBN, for use in property tests:
+//
+//   - the checksum is correct
+//   - Parse(n.String()) gives back n
+//   - To10().To13() is lossless for ISBN-13
+//   - a 979 ISBN has no ISBN-10: its To10 form (which only keeps the
+//     prefix so To13 can undo it) is flagged as not a real ISBN-10,
+//     and is itself a violation
+//   - Canonical() is idempotent
+//   - EquivalientTo is reflexive
+//
+// All violations are reported in the one error.
+func AssertInvariants(n *ISBN) error {
+	if n == nil {
+		return fmt.Errorf("ISBN invariants violated: nil ISBN")
+	}
+	var violations []string
+	if !n.isValid() {
+		violations = append(violations, "checksum is incorrect")
+	}
+	if p, err := Parse(n.String()); err != nil {
+		violations = append(violations, fmt.Sprintf("String() does not parse: %s", err))
+	} else if p.is13 != n.is13 || p.digits != n.digits || p.checksum != n.checksum || (n.is13 && p.prefix != n.prefix) {
+		violations = append(violations, fmt.Sprintf("Parse(String()) gave %s for %s", p, n))
+	}
+	if n.is979Form10() {
+		violations = append(violations, fmt.Sprintf("%s is the To10 form of a %s ISBN, not a real ISBN-10", n, digitString(n.prefix[:])))
+	}
+	if n.is13 {
+		n10 := n.To10()
+		if back := n10.To13(); *back != *n {
+			violations = append(violations, fmt.Sprintf("To10().To13() gave %s for %s", back, n))
+		}
+		if n.prefix != [3]byte{9, 7, 8} && !n10.is979Form10() {
+			violations = append(violations, fmt.Sprintf("To10() of %s is not flagged as having no ISBN-10", n))
+		}
+	}
+	if p, err := Parse(n.Canonical()); err != nil {
+		violations = append(violations, fmt.Sprintf("Canonical() does not parse: %s", err))
+	} else if p.Canonical() != n.Canonical() {
+		violations = append(violations, fmt.Sprintf("Canonical() is not idempotent: %s vs %s", p.Canonical(), n.Canonical()))
+	}
+	if !n.EquivalientTo(n) {
+		violations = append(violations, "EquivalientTo is not reflexive")
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("ISBN invariants violated: %s", strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+// To13Checked is To13 with the conversion verified: the input must have
+// decimal body digits and a correct checksum, and the ISBN-13 must have
+// an allowed prefix, the same nine body digits and a consistent check
+// digit. Any failure
+// means the value was corrupted or there is a bug, so it is cheap
+// insurance before persisting converted values.
+func (n *ISBN) To13Checked() (*ISBN, error) {
+	for _, d := range n.digits {
+		if d > 9 {
+			return nil, fmt.Errorf("ISBN body digit out of range before conversion: %d", d)
+		}
+	}
+	if !n.isValid() {
+		return nil, fmt.Errorf("ISBN checksum was incorrect before conversion: %s", n)
+	}
+	n13 := n.To13()
+	switch {
+	case !n13.is13 || !isAllowedPrefix(n13.prefix):
+		return nil, fmt.Errorf("ISBN-13 conversion gave a bad prefix: %s", n13)
+	case n13.digits != n.digits:
+		return nil, fmt.Errorf("ISBN-13 conversion changed the body: %s to %s", n, n13)
+	case n13.checksum != check13(n13.prefix, n13.digits):
+		return nil, fmt.Errorf("ISBN-13 conversion gave an inconsistent check digit: %s", n13)
+	}
+	return n13, nil
+}
+
+// DualCheckValid checks as much of both checksum schemes as the ISBN
+// holds. Only the check digit of its own form is stored (To10 and To13
+// compute the other one), so the other scheme cannot be validated
+// independently. What is checked is that the body digits are
+// decimal, that the stored check digit is correct for its own form, and
+// that an ISBN-10 is a real one, with a 978 ISBN-13 twin, rather than
+// the To10 form of a 979 ISBN, which has no ISBN-10 at all.
+func (n *ISBN) DualCheckValid() bool {
+	for _, d := range n.digits {
+		if d > 9 {
+			return false
+		}
+	}
+	return n.isValid() && !n.is979Form10()
+}
@@ -0,0 +1,113 @@
+package isbn
+
+import (
+	"testing"
+)
+
+func TestAssertInvariants(t *testing.T) {
+	inputs := []string{test979isbn}
+	for _, v := range tests {
+		if v.valid {
+			inputs = append(inputs, v.isbn10, v.isbn13)
+		}
+	}
+	for _, s := range inputs {
+		n, err := Parse(s)
+		if err != nil {
+			t.Errorf("Failed to parse `%s`, error: %s", s, err)
+			continue
+		}
+		for _, m := range []*ISBN{n, n.To10(), n.To13()} {
+			if m.is979Form10() {
+				continue
+			}
+			if err := AssertInvariants(m); err != nil {
+				t.Errorf("Invariants failed for %s: %s", m, err)
+			}
+		}
+	}
+	// a 979 ISBN has no ISBN-10
+	n979, _ := Parse(test979isbn)
+	if err := AssertInvariants(n979.To10()); err == nil {
+		t.Errorf("Expected invariants to fail for the To10 form of %s", n979)
+	}
+	// a corrupted ISBN should fail
+	corrupt := &ISBN{is13: true, prefix: [3]byte{9, 7, 8}, digits: [9]byte{0, 8, 0, 4, 4, 2, 9, 5, 7}, checksum: 4}
+	if err := AssertInvariants(corrupt); err == nil {
+		t.Errorf("Expected invariants to fail for a bad checksum")
+	}
+	if err := AssertInvariants(nil); err == nil {
+		t.Errorf("Expected invariants to fail for nil")
+	}
+}
+
+// corrupted returns a copy of the parsed ISBN, modified by corrupt
+func corrupted(t *testing.T, s string, corrupt func(n *ISBN)) *ISBN {
+	n, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Failed to parse `%s`, error: %s", s, err)
+	}
+	c := *n
+	corrupt(&c)
+	return &c
+}
+
+func TestTo13Checked(t *testing.T) {
+	for _, s := range []string{"080442957X", "9780804429573", test979isbn} {
+		n, err := Parse(s)
+		if err != nil {
+			t.Fatalf("Failed to parse `%s`, error: %s", s, err)
+		}
+		n13, err := n.To13Checked()
+		if err != nil {
+			t.Errorf("To13Checked of %s failed: %s", n, err)
+			continue
+		}
+		checkStringEqual(t, "To13Checked should match To13", n13.String(), n.To13().String())
+	}
+	bad := []*ISBN{
+		corrupted(t, "9780804429573", func(n *ISBN) { n.checksum = 4 }),
+		corrupted(t, "080442957X", func(n *ISBN) { n.digits[3] = 5 }),
+		// a body digit that isn't a digit at all
+		corrupted(t, "080442957X", func(n *ISBN) { n.digits[0] = 10; n.checksum = check10(n.digits) }),
+		corrupted(t, "9780804429573", func(n *ISBN) { n.prefix = [3]byte{9, 7, 7}; n.checksum = check13(n.prefix, n.digits) }),
+	}
+	for _, n := range bad {
+		if n13, err := n.To13Checked(); err == nil {
+			t.Errorf("Expected To13Checked of corrupted %v to fail, got %v", *n, *n13)
+		}
+	}
+}
+
+func TestDualCheckValid(t *testing.T) {
+	for _, s := range []string{"080442957X", "9780804429573", "0836220889", test979isbn} {
+		n, err := Parse(s)
+		if err != nil {
+			t.Fatalf("Failed to parse `%s`, error: %s", s, err)
+		}
+		for _, m := range []*ISBN{n, n.To10(), n.To13()} {
+			if m.is979Form10() {
+				continue
+			}
+			if !m.DualCheckValid() {
+				t.Errorf("Expected %s to pass both checks", m)
+			}
+		}
+	}
+	n979, _ := Parse(test979isbn)
+	bad := []*ISBN{
+		// an ISBN-13 carrying its ISBN-10 check digit passes mod-11, not mod-10
+		corrupted(t, "9780836218251", func(n *ISBN) { n.checksum = check10(n.digits) }),
+		// and an ISBN-10 carrying its ISBN-13 check digit the other way round
+		corrupted(t, "0836218256", func(n *ISBN) { n.checksum = check13([3]byte{9, 7, 8}, n.digits) }),
+		// a non-decimal body digit
+		corrupted(t, "9780804429573", func(n *ISBN) { n.digits[0] = 10; n.checksum = check13(n.prefix, n.digits) }),
+		// the To10 form of a 979 ISBN passes mod-11, but has no ISBN-10
+		n979.To10(),
+	}
+	for _, n := range bad {
+		if n.DualCheckValid() {
+			t.Errorf("Expected corrupted %v to fail the dual check", *n)
+		}
+	}
+}
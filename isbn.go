@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 )
 
 // ISBN represents the number in an intermediate form.
@@ -19,6 +20,16 @@ var allowedISBN13Prefixes = [][]byte{{9, 7, 8}, {9, 7, 9}}
 
 const urnPrefix = `urn:isbn:`
 
+// maxSeparatorLen is the longest UTF-8 encoding of a separator, see
+// isSeparator
+const maxSeparatorLen = 3
+
+// maxInputLen is the longest string, in bytes, Parse could accept: the
+// urn prefix and 13 digits with 4 separators (urn components aside).
+// Entry points that do work before calling Parse use it to reject giant
+// inputs up front.
+const maxInputLen = len(urnPrefix) + 13 + 4*maxSeparatorLen
+
 // convert the rune to it's isbn digit value, returning
 // -1 for invalid characters, which are stripped.
 func runeToISBNDigit(r rune) rune {
@@ -31,6 +42,21 @@ func runeToISBNDigit(r rune) rune {
 		return -1
 	}
 }
+
+// isSeparator reports whether r is a separator between the elements
+// of an ISBN: a hyphen, a space, or the middle dot (U+00B7) or thin
+// space (U+2009) of international typography. Separators are stripped
+// like any other character, but always count as one character toward
+// the length limit, however long their encoding.
+func isSeparator(r rune) bool {
+	switch r {
+	case '-', ' ', '\u00b7', '\u2009':
+		return true
+	default:
+		return false
+	}
+}
+
 func isbnDigitToByte(r byte) byte {
 	switch true {
 	case r >= 0 && r <= 9:
@@ -50,40 +76,92 @@ func Validate(s string) bool {
 
 // Parse turns a string into an ISBN, or throws an error.
 // The string must be contain only digits and hyphens,
-// expect for the optional prefix `urn:isbn:`
+// expect for the optional prefix `urn:isbn:` (and the urn's
+// components, see ParseURNParams)
 func Parse(s string) (*ISBN, error) {
+	parsed := &ISBN{}
+	if err := parseInto(s, parsed); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// parseInto does the work of Parse, filling in an existing ISBN so that
+// batch callers can avoid an allocation per ISBN.
+func parseInto(s string, parsed *ISBN) error {
+	if err := parseDigits(s, parsed); err != nil {
+		return err
+	}
+	if !parsed.isValid() {
+		return fmt.Errorf("ISBN checksum was incorrect")
+	}
+	return nil
+}
+
+// parseDigits checks the format of the string and fills in the digits,
+// but leaves the checksum unchecked.
+func parseDigits(s string, parsed *ISBN) error {
+	return parseDigitsMax(s, parsed, legacySeparators)
+}
+
+// legacySeparators is the maxSeparators of parseDigitsMax for the
+// default rule: 13 characters plus 4, separators or not.
+const legacySeparators = -1
+
+// parseDigitsMax is parseDigits allowing at most maxSeparators
+// separators, and as many other stripped characters as there are
+// digits short of 13 plus maxSeparators (see legacySeparators).
+func parseDigitsMax(s string, parsed *ISBN, maxSeparators int) error {
 	if strings.HasPrefix(s, urnPrefix) {
-		s = s[len(urnPrefix):]
+		// any RFC 8141 components are not part of the ISBN
+		s, _ = splitURNComponents(s[len(urnPrefix):])
 	}
 	// now strip unwanted characters.
 	// Note that the string itseflf may contain hyphens or spaces
 	// but should not contain more than 4. So we can check length
-	// here.
-	if len(s) > 13+4 {
-		return nil, fmt.Errorf("Invalid ISBN format")
+	// here, in bytes, allowing for multi-byte separators.
+	extra := maxSeparators
+	if maxSeparators == legacySeparators {
+		extra = 4
+	}
+	if len(s) > 13+extra*maxSeparatorLen {
+		return fmt.Errorf("Invalid ISBN format")
+	}
+	// strip unwanted characters, counting separators as one character
+	var buf [13 + 4*maxSeparatorLen]byte
+	m := buf[:0]
+	width, separators := len(s), 0
+	for _, r := range s {
+		if d := runeToISBNDigit(r); d != -1 {
+			m = append(m, byte(d))
+		} else if isSeparator(r) {
+			width -= utf8.RuneLen(r) - 1
+			separators++
+		}
+	}
+	if width > 13+extra || (maxSeparators != legacySeparators && separators > maxSeparators) {
+		return fmt.Errorf("Invalid ISBN format")
 	}
-	// strip unwanted characters.
-	m := strings.Map(runeToISBNDigit, s)
 	// now it should be either 10 or 13 digits
 	is13 := len(m) == 13
 	if len(m) != 10 && !is13 {
-		return nil, fmt.Errorf("Invalid ISBN digit count")
+		return fmt.Errorf("Invalid ISBN digit count")
 	}
-	parsed := &ISBN{is13: is13, digits: [9]byte{}}
+	*parsed = ISBN{is13: is13, digits: [9]byte{}}
 	// if 13, check prefix is 978
 	offset := 0
 	if is13 {
 		// allowed prefixes? 978 and 979?
 		parsed.prefix = [3]byte{m[0], m[1], m[2]}
 		if !isAllowedPrefix(parsed.prefix) {
-			return nil, fmt.Errorf("Unexpected ISBN-13 prefix: %s", s[0:3])
+			return fmt.Errorf("Unexpected ISBN-13 prefix: %s", s[0:3])
 		}
 		offset = 3
 	}
 
-	for i, c := range []byte(m[offset:]) {
+	for i, c := range m[offset:] {
 		if c == 10 && (is13 || i != 9) {
-			return nil, fmt.Errorf("Unexpected character in ISBN (X can only be the final digit of an ISBN-10)")
+			return fmt.Errorf("Unexpected character in ISBN (X can only be the final digit of an ISBN-10)")
 		}
 		if i == 9 {
 			parsed.checksum = c
@@ -91,10 +169,7 @@ func Parse(s string) (*ISBN, error) {
 			parsed.digits[i] = c
 		}
 	}
-	if !parsed.isValid() {
-		return nil, fmt.Errorf("ISBN checksum was incorrect")
-	}
-	return parsed, nil
+	return nil
 }
 
 func isAllowedPrefix(p [3]byte) bool {
@@ -115,12 +190,24 @@ func (n *ISBN) isValid() bool {
 	return check10(n.digits) == n.checksum
 }
 
+// ISBN10Weights are the weights of the ISBN-10 digits, check digit
+// last, in the checksum: the weighted sum of a valid ISBN-10 is a
+// multiple of 11. They are shared with the checksum code, so must not
+// be modified.
+var ISBN10Weights = [10]int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+
+// ISBN13Weights are the weights of the ISBN-13 digits, check digit
+// last, in the checksum: the weighted sum of a valid ISBN-13 (or any
+// GTIN-13) is a multiple of 10. They are shared with the checksum code,
+// so must not be modified.
+var ISBN13Weights = [13]int{1, 3, 1, 3, 1, 3, 1, 3, 1, 3, 1, 3, 1}
+
 // returns the checksum digit value of the nine digits using
 // the ISBN-10 checksum algorithm
 func check10(digits [9]byte) byte {
 	sum := 0
 	for i, d := range digits {
-		sum += int(d) * (10 - i)
+		sum += int(d) * ISBN10Weights[i]
 	}
 	m := sum % 11
 	if m == 0 {
@@ -132,14 +219,12 @@ func check10(digits [9]byte) byte {
 // returns the checksum digit value of the nine digits using
 // the ISBN-13 checksum algorithm, prefix *assumed* to be 978
 func check13(prefix [3]byte, digits [9]byte) byte {
-	sum := 1*int(prefix[0]) + 3*int(prefix[1]) + 1*int(prefix[2])
-	var weight int
+	sum := 0
+	for i, d := range prefix {
+		sum += int(d) * ISBN13Weights[i]
+	}
 	for i, d := range digits {
-		weight = 1
-		if i%2 == 0 { // even indices weigh 3 (we started from digit 4: weight = 3)
-			weight = 3
-		}
-		sum += int(d) * weight
+		sum += int(d) * ISBN13Weights[len(prefix)+i]
 	}
 	m := sum % 10
 	if m == 0 {
@@ -148,6 +233,31 @@ func check13(prefix [3]byte, digits [9]byte) byte {
 	return byte(10 - m)
 }
 
+// ChecksumBreakdown explains the checksum of s, which must have the
+// format of an ISBN but may have the wrong check digit. It returns the
+// check digit given, the one expected, and the weighted contribution
+// of each other digit to the checksum sum, in order (9 for ISBN-10, 12
+// for ISBN-13). Check digits are digit values, so X is 10.
+func ChecksumBreakdown(s string) (got byte, want byte, perDigit []int, err error) {
+	n := &ISBN{}
+	if err := parseDigits(s, n); err != nil {
+		return 0, 0, nil, err
+	}
+	if n.is13 {
+		for i, d := range n.prefix {
+			perDigit = append(perDigit, int(d)*ISBN13Weights[i])
+		}
+		for i, d := range n.digits {
+			perDigit = append(perDigit, int(d)*ISBN13Weights[len(n.prefix)+i])
+		}
+		return n.checksum, check13(n.prefix, n.digits), perDigit, nil
+	}
+	for i, d := range n.digits {
+		perDigit = append(perDigit, int(d)*ISBN10Weights[i])
+	}
+	return n.checksum, check10(n.digits), perDigit, nil
+}
+
 // To10 returns the ISBN-10 version of this ISBN, if it already is
 // ISBN-10, this returns it's input
 // Note, that we keep the prefix, so if this was a 979 prefixed ISBN-13
@@ -193,22 +303,39 @@ func (n *ISBN) Is10() bool {
 	return !n.is13
 }
 
+// Generation classifies the ISBN as "isbn10", "isbn13-978" or
+// "isbn13-979", by its form and prefix.
+func (n *ISBN) Generation() string {
+	if !n.is13 {
+		return "isbn10"
+	}
+	return "isbn13-" + digitString(n.prefix[:])
+}
+
 // String formats ISBN-10 as just the digits, ISBN-13 gets a single
 // hyphen after the prefix
 func (n *ISBN) String() string {
-	base := make([]byte, 10)
-	for i, d := range n.digits {
-		base[i] = isbnDigitToByte(d)
-	}
-	base[9] = isbnDigitToByte(n.checksum)
+	return string(n.appendString(make([]byte, 0, 14)))
+}
+
+// appendString appends the String form to dst
+func (n *ISBN) appendString(dst []byte) []byte {
+	return n.appendDigits(dst, "-")
+}
+
+// appendDigits appends the digits to dst, with sep between the prefix
+// and the rest of an ISBN-13
+func (n *ISBN) appendDigits(dst []byte, sep string) []byte {
 	if n.is13 {
-		pre := make([]byte, 3)
-		for i, d := range n.prefix {
-			pre[i] = isbnDigitToByte(d)
+		for _, d := range n.prefix {
+			dst = append(dst, isbnDigitToByte(d))
 		}
-		return string(pre) + "-" + string(base)
+		dst = append(dst, sep...)
+	}
+	for _, d := range n.digits {
+		dst = append(dst, isbnDigitToByte(d))
 	}
-	return string(base)
+	return append(dst, isbnDigitToByte(n.checksum))
 }
 
 // EquivalientTo checks equivalence, not strict equality
@@ -225,12 +352,113 @@ func (n *ISBN) EquivalientTo(other *ISBN) bool {
 	return true
 }
 
+// EquivalentWithin checks whether the ISBN-13 forms differ in at most
+// maxDiff digit positions, prefix included. The check digit is not
+// compared, as it follows from the others.
+func (n *ISBN) EquivalentWithin(other *ISBN, maxDiff int) bool {
+	if other == nil || n == nil {
+		return false
+	}
+	a, b := n.To13(), other.To13()
+	diff := 0
+	for i, d := range a.prefix {
+		if d != b.prefix[i] {
+			diff++
+		}
+	}
+	for i, d := range a.digits {
+		if d != b.digits[i] {
+			diff++
+		}
+	}
+	return diff <= maxDiff
+}
+
+// EquivalentWithinOne checks whether the ISBN-13 forms differ in at
+// most one digit, see EquivalentWithin
+func (n *ISBN) EquivalentWithinOne(other *ISBN) bool {
+	return n.EquivalentWithin(other, 1)
+}
+
 // ToURN retusn the string urn for this ISBN
 func (n *ISBN) ToURN() string {
 	return urnPrefix + n.String()
 }
 
-// Canonical returns the urn form of the ISBN-13 version
+// CanonicalForm is the format Canonical uses, see
+// SetDefaultCanonicalForm
+type CanonicalForm int
+
+const (
+	// CanonicalURN is the urn form of the ISBN-13,
+	// `urn:isbn:978-0804429573`. This is the default.
+	CanonicalURN CanonicalForm = iota
+	// CanonicalBare13 is just the ISBN-13 digits, `9780804429573`
+	CanonicalBare13
+	// CanonicalString13 is the String form of the ISBN-13,
+	// `978-0804429573`
+	CanonicalString13
+)
+
+var defaultCanonicalForm = CanonicalURN
+
+// SetDefaultCanonicalForm changes the format Canonical returns for
+// the whole program. This is global state and is not safe to change
+// while other goroutines use Canonical, so set it once at init.
+func SetDefaultCanonicalForm(f CanonicalForm) {
+	if f < CanonicalURN || f > CanonicalString13 {
+		panic("Invalid ISBN canonical form")
+	}
+	defaultCanonicalForm = f
+}
+
+// Canonical returns the ISBN-13 version in the canonical form, by
+// default the urn (see SetDefaultCanonicalForm)
 func (n *ISBN) Canonical() string {
-	return n.To13().ToURN()
+	return string(n.appendCanonical(make([]byte, 0, len(urnPrefix)+14)))
+}
+
+// appendCanonical appends the Canonical form to dst
+func (n *ISBN) appendCanonical(dst []byte) []byte {
+	return n.To13().appendForm(dst, defaultCanonicalForm)
+}
+
+// appendForm appends this ISBN, without converting it, in form f
+func (n *ISBN) appendForm(dst []byte, f CanonicalForm) []byte {
+	switch f {
+	case CanonicalBare13:
+		return n.appendDigits(dst, "")
+	case CanonicalString13:
+		return n.appendString(dst)
+	default:
+		return n.appendString(append(dst, urnPrefix...))
+	}
+}
+
+// EqualsCanonicalString checks whether the canonical form of this ISBN
+// is exactly `canonical`, which must itself already be a canonical form
+// (e.g. one stored from an earlier call to Canonical). It saves parsing
+// the stored side of a comparison.
+func (n *ISBN) EqualsCanonicalString(canonical string) bool {
+	var buf [32]byte
+	return string(n.appendCanonical(buf[:0])) == canonical
+}
+
+// WriteToBuffer writes the Canonical form to b directly rather than
+// through io.Writer, for tight loops reusing a buffer. There is no
+// error as a bytes.Buffer never fails to write.
+func (n *ISBN) WriteToBuffer(b *bytes.Buffer) {
+	var buf [32]byte
+	b.Write(n.appendCanonical(buf[:0]))
+}
+
+// IsCanonical checks whether s is already exactly in the canonical form
+// (see SetDefaultCanonicalForm), so a normalization pass can skip
+// rewriting it. It returns the parse error if s is not a valid ISBN.
+func IsCanonical(s string) (bool, error) {
+	n, err := Parse(s)
+	if err != nil {
+		return false, err
+	}
+	return n.EqualsCanonicalString(s), nil
 }
@@ -1,7 +1,13 @@
 package isbn
 
 import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 // most of the testing data for this package was lifted from:
@@ -132,3 +138,350 @@ func TestISBN13_979_Prefix(t *testing.T) {
 	// it should conserve the prefix.
 	checkStringEqual(t, "Conversion of ISBN-13 To10() and back should be lossless", n.String(), n.To10().To13().String())
 }
+
+func TestGeneration(t *testing.T) {
+	cases := []struct {
+		isbn       string
+		generation string
+	}{
+		{"080442957X", "isbn10"},
+		{"9780804429573", "isbn13-978"},
+		{test979isbn, "isbn13-979"},
+		{"979-10-90636-07-1", "isbn13-979"},
+	}
+	for _, c := range cases {
+		n, err := Parse(c.isbn)
+		if err != nil {
+			t.Errorf("Failed to parse `%s`, error: %s", c.isbn, err)
+			continue
+		}
+		checkStringEqual(t, "Generation of "+c.isbn, n.Generation(), c.generation)
+	}
+}
+
+func TestEqualsCanonicalString(t *testing.T) {
+	n, err := Parse("080442957X")
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	if !n.EqualsCanonicalString("urn:isbn:978-0804429573") {
+		t.Errorf("Expected %s to equal its canonical form", n)
+	}
+	for _, s := range []string{"urn:isbn:978-0836220889", "9780804429573", ""} {
+		if n.EqualsCanonicalString(s) {
+			t.Errorf("Expected %s not to equal `%s`", n, s)
+		}
+	}
+}
+
+func TestIsCanonical(t *testing.T) {
+	for _, v := range []struct {
+		s         string
+		canonical bool
+	}{
+		{"urn:isbn:978-0804429573", true},
+		{"9780804429573", false},
+		{"080442957X", false},
+		{"urn:isbn:9780804429573", false},
+	} {
+		ok, err := IsCanonical(v.s)
+		if err != nil {
+			t.Errorf("Failed to check `%s`, error: %s", v.s, err)
+		} else if ok != v.canonical {
+			t.Errorf("Expected IsCanonical(`%s`) to be %t", v.s, v.canonical)
+		}
+	}
+	if _, err := IsCanonical("9780804429574"); err == nil {
+		t.Errorf("Expected an error for an invalid ISBN")
+	}
+
+	defer SetDefaultCanonicalForm(CanonicalURN)
+	SetDefaultCanonicalForm(CanonicalBare13)
+	if ok, _ := IsCanonical("9780804429573"); !ok {
+		t.Errorf("Expected bare digits to be canonical with CanonicalBare13")
+	}
+}
+
+func TestWriteToBuffer(t *testing.T) {
+	n, err := Parse("080442957X")
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	var b bytes.Buffer
+	b.WriteString("isbn=")
+	n.WriteToBuffer(&b)
+	checkStringEqual(t, "Buffer contents", b.String(), "isbn="+n.Canonical())
+}
+
+func BenchmarkWriteToBuffer(b *testing.B) {
+	n, _ := Parse("080442957X")
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		n.WriteToBuffer(&buf)
+	}
+}
+
+func BenchmarkFprintBuffer(b *testing.B) {
+	n, _ := Parse("080442957X")
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		fmt.Fprint(&buf, n)
+	}
+}
+
+func BenchmarkEqualsCanonicalString(b *testing.B) {
+	n, _ := Parse("080442957X")
+	stored := n.Canonical()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		n.EqualsCanonicalString(stored)
+	}
+}
+
+func BenchmarkEqualsParseBothSides(b *testing.B) {
+	n, _ := Parse("080442957X")
+	stored := n.Canonical()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		other, _ := Parse(stored)
+		_ = n.Canonical() == other.Canonical()
+	}
+}
+
+func TestChecksumBreakdown(t *testing.T) {
+	cases := []struct {
+		isbn      string
+		got, want byte
+		perDigit  []int
+		valid     bool
+	}{
+		{"080442957X", 10, 10, []int{0, 72, 0, 28, 24, 10, 36, 15, 14}, true},
+		{"0804429571", 1, 10, []int{0, 72, 0, 28, 24, 10, 36, 15, 14}, true},
+		{"9780804429573", 3, 3, []int{9, 21, 8, 0, 8, 0, 4, 12, 2, 27, 5, 21}, true},
+		{"978-0-8044-2957-4", 4, 3, []int{9, 21, 8, 0, 8, 0, 4, 12, 2, 27, 5, 21}, true},
+		// wrong length or characters
+		{"08044295", 0, 0, nil, false},
+		{"08044295XX", 0, 0, nil, false},
+		{"9770804429573", 0, 0, nil, false},
+	}
+	for _, c := range cases {
+		got, want, perDigit, err := ChecksumBreakdown(c.isbn)
+		if !c.valid {
+			if err == nil {
+				t.Errorf("Expected ChecksumBreakdown(%s) to fail", c.isbn)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Failed ChecksumBreakdown(%s), error: %s", c.isbn, err)
+			continue
+		}
+		if got != c.got || want != c.want || !reflect.DeepEqual(perDigit, c.perDigit) {
+			t.Errorf("ChecksumBreakdown(%s) = %d, %d, %v, expected %d, %d, %v", c.isbn, got, want, perDigit, c.got, c.want, c.perDigit)
+		}
+	}
+}
+
+func TestSetDefaultCanonicalForm(t *testing.T) {
+	defer SetDefaultCanonicalForm(CanonicalURN)
+	n, err := Parse("080442957X")
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	checkStringEqual(t, "Default canonical form", n.Canonical(), "urn:isbn:978-0804429573")
+	cases := []struct {
+		form      CanonicalForm
+		canonical string
+	}{
+		{CanonicalBare13, "9780804429573"},
+		{CanonicalString13, "978-0804429573"},
+		{CanonicalURN, "urn:isbn:978-0804429573"},
+	}
+	for _, c := range cases {
+		SetDefaultCanonicalForm(c.form)
+		checkStringEqual(t, "Configured canonical form", n.Canonical(), c.canonical)
+		checkStringEqual(t, "Configured canonical form of the ISBN-13", n.To13().Canonical(), c.canonical)
+		if !n.EqualsCanonicalString(c.canonical) {
+			t.Errorf("Expected EqualsCanonicalString to follow the configured form %d", c.form)
+		}
+	}
+}
+
+func TestTypographicSeparators(t *testing.T) {
+	// middle dots and thin spaces behave exactly like hyphens
+	for _, sep := range []string{"\u00b7", "\u2009"} {
+		for _, v := range tests {
+			for _, s := range []string{v.isbn10, v.isbn13} {
+				typographic := strings.Replace(s, "-", sep, -1)
+				if Validate(typographic) != Validate(s) {
+					t.Errorf("Expected `%s` to be valid like `%s`: %t", typographic, s, Validate(s))
+				}
+			}
+		}
+	}
+	for _, v := range []struct {
+		s     string
+		valid bool
+	}{
+		{"978\u00b70\u00b78044\u00b72957\u00b73", true},
+		{"978\u20090\u20098044\u20092957\u20093", true},
+		{"978\u00b70-8044\u20092957 3", true},
+		// 5 separators on an ISBN-13 is too many
+		{"97\u00b78\u00b70\u00b78044\u00b72957\u00b73", false},
+		{"97\u20098\u20090\u20098044\u20092957\u20093", false},
+		// other characters still count their bytes
+		{"978\u00e90\u00e98044\u00e92957\u00e93", false},
+	} {
+		n, err := Parse(v.s)
+		if v.valid && err != nil {
+			t.Errorf("Failed to parse `%s`, error: %s", v.s, err)
+		} else if v.valid {
+			checkStringEqual(t, "ISBN with typographic separators", n.String(), "978-0804429573")
+		} else if err == nil {
+			t.Errorf("Expected `%s` to be invalid", v.s)
+		}
+	}
+}
+
+// giant inputs must be rejected quickly and without buffering them
+func TestPathologicalInputs(t *testing.T) {
+	giant := strings.Repeat("9", 1000000)
+	starred := giant + "*"
+	lines := "0836220889\n" + giant + "\n0836220889"
+	marc := giant + " (pbk.)"
+	csv := "ISBN,title\n" + giant + ",Giant\n"
+	tsv := "title\tISBN\n" + "Giant\t" + giant + "\n" + "Calvin and Hobbes\t0836220889\n"
+	checks := map[string]func(){
+		"Parse": func() { Parse(giant) },
+		"Parser.Parse": func() {
+			(&Parser{AllowAltCheckGlyphs: true}).Parse(starred)
+		},
+		"ValidateLines": func() {
+			invalid, err := ValidateLines(strings.NewReader(lines))
+			if err != nil || !reflect.DeepEqual(invalid, []int{2}) {
+				t.Errorf("ValidateLines gave %v, %v for a giant line", invalid, err)
+			}
+		},
+		"ExtractFromMARC020": func() {
+			if _, _, err := ExtractFromMARC020(marc); err == nil {
+				t.Errorf("Expected a giant MARC subfield to fail")
+			}
+		},
+		"ReadTSV": func() {
+			var valid []bool
+			err := ReadTSV(strings.NewReader(tsv), "ISBN", func(_ map[string]string, _ *ISBN, err error) bool {
+				valid = append(valid, err == nil)
+				return true
+			})
+			if err != nil || !reflect.DeepEqual(valid, []bool{false, true}) {
+				t.Errorf("ReadTSV gave %v, %v for a giant row", valid, err)
+			}
+		},
+		"ReadExcelCSV": func() {
+			err := ReadExcelCSV(strings.NewReader(csv), 0, func(*ISBN, error) bool { return true })
+			if err == nil {
+				t.Errorf("Expected ReadExcelCSV to fail on a giant line")
+			}
+		},
+		"CanonicalizeColumn": func() { CanonicalizeColumn([]string{giant}) },
+		"FormatReport":       func() { FormatReport(ParseAll([]string{giant})) },
+	}
+	for name, check := range checks {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		check()
+		elapsed := time.Since(start)
+		runtime.ReadMemStats(&after)
+		if elapsed > time.Second {
+			t.Errorf("%s took %s on a giant input", name, elapsed)
+		}
+		if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 64*1024 {
+			t.Errorf("%s allocated %d bytes on a giant input", name, allocated)
+		}
+	}
+}
+
+func TestEquivalentWithin(t *testing.T) {
+	cases := []struct {
+		a, b string
+		diff int
+	}{
+		{"080442957X", "9780804429573", 0},
+		// one body digit
+		{"0836218256", "0836218353", 1},
+		// 978 and 979 differ in the prefix
+		{"978-5000000236", test979isbn, 1},
+		{"0836220889", "0836218256", 4},
+	}
+	for _, c := range cases {
+		a, err := Parse(c.a)
+		if err != nil {
+			t.Fatalf("Failed to parse `%s`, error: %s", c.a, err)
+		}
+		b, err := Parse(c.b)
+		if err != nil {
+			t.Fatalf("Failed to parse `%s`, error: %s", c.b, err)
+		}
+		if a.EquivalentWithinOne(b) != (c.diff <= 1) {
+			t.Errorf("EquivalentWithinOne(%s, %s) should be %v", c.a, c.b, c.diff <= 1)
+		}
+		for max := 0; max <= 5; max++ {
+			if a.EquivalentWithin(b, max) != (c.diff <= max) {
+				t.Errorf("EquivalentWithin(%s, %s, %d) should be %v", c.a, c.b, max, c.diff <= max)
+			}
+		}
+	}
+	var n *ISBN
+	if n.EquivalentWithinOne(nil) {
+		t.Errorf("nil ISBNs should not be equivalent")
+	}
+}
+
+func TestWeights(t *testing.T) {
+	for i, w := range ISBN10Weights {
+		if w != 10-i {
+			t.Errorf("ISBN-10 weight %d is %d, expected %d", i, w, 10-i)
+		}
+	}
+	for i, w := range ISBN13Weights {
+		expected := 1
+		if i%2 == 1 {
+			expected = 3
+		}
+		if w != expected {
+			t.Errorf("ISBN-13 weight %d is %d, expected %d", i, w, expected)
+		}
+	}
+	// every valid ISBN has a weighted sum that is a multiple of the modulus
+	for _, v := range tests {
+		if !v.valid {
+			continue
+		}
+		for _, s := range []string{v.isbn10, v.isbn13} {
+			n, err := Parse(s)
+			if err != nil {
+				t.Fatalf("Failed to parse `%s`, error: %s", s, err)
+			}
+			sum, mod := 0, 11
+			if n.is13 {
+				mod = 10
+				for i, d := range n.allDigits() {
+					sum += int(d) * ISBN13Weights[i]
+				}
+			} else {
+				for i, d := range n.allDigits() {
+					sum += int(d) * ISBN10Weights[i]
+				}
+			}
+			if sum%mod != 0 {
+				t.Errorf("Weighted sum of %s is %d, not a multiple of %d", s, sum, mod)
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package isbn
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// NormalizeJSONFields rewrites a JSON document so that every string
+// value of an object member named in fields, at any depth, is in the
+// canonical form. Values that are not valid ISBNs, or not strings, are
+// left alone, and everything but the rewritten strings is kept byte
+// for byte, including member order and whitespace.
+func NormalizeJSONFields(data []byte, fields []string) ([]byte, error) {
+	return normalizeJSONFields(data, fields, false)
+}
+
+// NormalizeJSONFieldsStrict is NormalizeJSONFields, but a string value
+// of a named member that is not a valid ISBN is an error, naming the
+// member. Non-strings are still left alone.
+func NormalizeJSONFieldsStrict(data []byte, fields []string) ([]byte, error) {
+	return normalizeJSONFields(data, fields, true)
+}
+
+// normalizeJSONFields does the work of NormalizeJSONFields, failing on
+// invalid ISBNs if strict
+func normalizeJSONFields(data []byte, fields []string, strict bool) ([]byte, error) {
+	match := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		match[f] = true
+	}
+	// the containers we are in, and for objects whether the next token
+	// is a member name and the name of the current member
+	type container struct {
+		object    bool
+		expectKey bool
+		key       string
+	}
+	var stack []container
+	var out []byte
+	written := 0
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	for {
+		start := dec.InputOffset()
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var top *container
+		if len(stack) > 0 {
+			top = &stack[len(stack)-1]
+		}
+		if d, ok := tok.(json.Delim); ok {
+			switch d {
+			case '{', '[':
+				if top != nil && top.object {
+					top.expectKey = true
+				}
+				stack = append(stack, container{object: d == '{', expectKey: true})
+			default:
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+		if top == nil || !top.object {
+			continue
+		}
+		s, isString := tok.(string)
+		if top.expectKey {
+			top.key, top.expectKey = s, false
+			continue
+		}
+		top.expectKey = true
+		if !isString || !match[top.key] {
+			continue
+		}
+		n, err := Parse(s)
+		if err != nil {
+			if strict {
+				return nil, fmt.Errorf("Invalid ISBN in JSON member %q: %s", top.key, err)
+			}
+			continue
+		}
+		canonical, _ := json.Marshal(n.Canonical())
+		// the token may be preceded by whitespace and a separator
+		end := int(dec.InputOffset())
+		quote := int(start) + bytes.IndexByte(data[start:end], '"')
+		out = append(append(out, data[written:quote]...), canonical...)
+		written = end
+	}
+	return append(out, data[written:]...), nil
+}
@@ -0,0 +1,89 @@
+package isbn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeJSONFields(t *testing.T) {
+	cases := []struct {
+		input    string
+		fields   []string
+		expected string
+		valid    bool
+	}{
+		{
+			`{"isbn": "0-8044-2957-X", "title": "0836220889"}`,
+			[]string{"isbn"},
+			`{"isbn": "urn:isbn:978-0804429573", "title": "0836220889"}`,
+			true,
+		},
+		// nested, in arrays, order and formatting kept
+		{
+			"{\n  \"z\": 1,\n  \"books\": [\n    {\"isbn13\" : \"9780836220889\", \"n\": [1, 2.50]},\n    {\"isbn13\":\"0836218256\"}\n  ],\n  \"a\": null\n}",
+			[]string{"isbn13"},
+			"{\n  \"z\": 1,\n  \"books\": [\n    {\"isbn13\" : \"urn:isbn:978-0836220889\", \"n\": [1, 2.50]},\n    {\"isbn13\":\"urn:isbn:978-0836218251\"}\n  ],\n  \"a\": null\n}",
+			true,
+		},
+		// invalid ISBNs and non-strings are left alone, as are keys
+		{
+			`{"isbn": "badformat!", "other": {"isbn": 9780836220889}, "0836220889": "isbn"}`,
+			[]string{"isbn", "0836220889"},
+			`{"isbn": "badformat!", "other": {"isbn": 9780836220889}, "0836220889": "isbn"}`,
+			true,
+		},
+		// a member named like a field inside an array is not confused with
+		// the array's own member name
+		{
+			`{"isbn": ["0836220889", {"x": "0836220889"}], "y": "0836220889"}`,
+			[]string{"isbn"},
+			`{"isbn": ["0836220889", {"x": "0836220889"}], "y": "0836220889"}`,
+			true,
+		},
+		// escaped strings
+		{
+			`{"isbn": "0836220889 ", "t": "a\"b"}`,
+			[]string{"isbn"},
+			`{"isbn": "urn:isbn:978-0836220889", "t": "a\"b"}`,
+			true,
+		},
+		{`{"isbn": }`, []string{"isbn"}, "", false},
+	}
+	for _, c := range cases {
+		out, err := NormalizeJSONFields([]byte(c.input), c.fields)
+		if !c.valid {
+			if err == nil {
+				t.Errorf("Expected NormalizeJSONFields(%s) to fail, got %s", c.input, out)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Failed NormalizeJSONFields(%s), error: %s", c.input, err)
+			continue
+		}
+		checkStringEqual(t, "Normalized JSON", string(out), c.expected)
+	}
+}
+
+func TestNormalizeJSONFieldsStrict(t *testing.T) {
+	out, err := NormalizeJSONFieldsStrict([]byte(`{"isbn": "0-8044-2957-X", "other": {"isbn": null}}`), []string{"isbn"})
+	if err != nil {
+		t.Fatalf("Failed NormalizeJSONFieldsStrict, error: %s", err)
+	}
+	checkStringEqual(t, "Strictly normalized JSON", string(out), `{"isbn": "urn:isbn:978-0804429573", "other": {"isbn": null}}`)
+
+	input := []byte(`{"isbn": "0836220889", "isbn13": "badformat!"}`)
+	out, err = NormalizeJSONFieldsStrict(input, []string{"isbn", "isbn13"})
+	if err == nil {
+		t.Fatalf("Expected NormalizeJSONFieldsStrict to fail, got %s", out)
+	}
+	if !strings.Contains(err.Error(), `"isbn13"`) {
+		t.Errorf("Expected the error to name the member, got %s", err)
+	}
+	// the lenient mode leaves it alone
+	out, err = NormalizeJSONFields(input, []string{"isbn", "isbn13"})
+	if err != nil {
+		t.Fatalf("Failed NormalizeJSONFields, error: %s", err)
+	}
+	checkStringEqual(t, "Normalized JSON", string(out), `{"isbn": "urn:isbn:978-0836220889", "isbn13": "badformat!"}`)
+}
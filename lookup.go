@@ -0,0 +1,22 @@
+package isbn
+
+// Lookup finds n in an index keyed by Canonical strings, also trying
+// the ISBN-10 in the same form (e.g. `urn:isbn:080442957X`) for a 978
+// number, so that an index built from either form still hits. 979
+// numbers have no ISBN-10, so only the Canonical key is tried.
+func Lookup[V any](n *ISBN, index map[string]V) (V, bool) {
+	var zero V
+	if n == nil {
+		return zero, false
+	}
+	if v, ok := index[n.Canonical()]; ok {
+		return v, true
+	}
+	n13 := n.To13()
+	if n13.prefix != [3]byte{9, 7, 8} {
+		return zero, false
+	}
+	var buf [32]byte
+	v, ok := index[string(n13.To10().appendForm(buf[:0], defaultCanonicalForm))]
+	return v, ok
+}
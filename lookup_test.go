@@ -0,0 +1,43 @@
+package isbn
+
+import (
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	n10, _ := Parse("080442957X")
+	n13, _ := Parse("9780804429573")
+	n979, _ := Parse("9791234567896")
+	for _, index := range []map[string]int{
+		{"urn:isbn:978-0804429573": 1},
+		{"urn:isbn:080442957X": 1},
+	} {
+		for _, n := range []*ISBN{n10, n13} {
+			if v, ok := Lookup(n, index); !ok || v != 1 {
+				t.Errorf("Expected to find %s in %v, got %d, %t", n, index, v, ok)
+			}
+		}
+	}
+	// 979 numbers only have the 13 key
+	index := map[string]string{
+		"urn:isbn:979-1234567896": "found",
+		// the ISBN-10 the To10 hack would give
+		"urn:isbn:123456789X": "wrong",
+	}
+	if v, ok := Lookup(n979, index); !ok || v != "found" {
+		t.Errorf("Expected to find %s, got %q, %t", n979, v, ok)
+	}
+	delete(index, "urn:isbn:979-1234567896")
+	if v, ok := Lookup(n979, index); ok {
+		t.Errorf("Expected not to find %s, got %q", n979, v)
+	}
+	if _, ok := Lookup(nil, index); ok {
+		t.Errorf("Expected not to find a nil ISBN")
+	}
+
+	defer SetDefaultCanonicalForm(CanonicalURN)
+	SetDefaultCanonicalForm(CanonicalBare13)
+	if _, ok := Lookup(n13, map[string]bool{"080442957X": true}); !ok {
+		t.Errorf("Expected to find %s by its bare ISBN-10", n13)
+	}
+}
@@ -0,0 +1,64 @@
+package isbn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExtractFromMARC020 parses the content of a MARC 020 $a subfield,
+// which is the ISBN (digits and hyphens only) optionally followed by
+// a qualifier, e.g. `9780804429573 (pbk.)`. The qualifier is returned
+// as written, with surrounding whitespace removed.
+func ExtractFromMARC020(subfield string) (*ISBN, string, error) {
+	s := strings.TrimSpace(subfield)
+	// only look as far as the longest possible ISBN
+	window := s
+	if len(window) > maxInputLen+1 {
+		window = window[:maxInputLen+1]
+	}
+	end := strings.IndexFunc(window, func(r rune) bool {
+		return r != '-' && runeToISBNDigit(r) == -1
+	})
+	if end == -1 {
+		if len(window) > maxInputLen {
+			return nil, "", fmt.Errorf("Invalid ISBN format")
+		}
+		end = len(s)
+	}
+	if end == 0 {
+		return nil, "", fmt.Errorf("No ISBN at the start of MARC 020 subfield: %s", subfield)
+	}
+	n, err := Parse(s[:end])
+	if err != nil {
+		return nil, "", err
+	}
+	return n, strings.TrimSpace(s[end:]), nil
+}
+
+// ToMARC020 formats the ISBN-13 as the content of a MARC 020 $a
+// subfield, followed by the qualifier in parentheses if there is one,
+// e.g. `9780804429573 (pbk.)`. A qualifier already in parentheses or
+// brackets, as ExtractFromMARC020 returns them, is not wrapped again.
+func (n *ISBN) ToMARC020(qualifier string) string {
+	return appendMARCQualifier(n.To13().appendDigits(make([]byte, 0, 32), ""), qualifier)
+}
+
+// ToMARC020Original is ToMARC020 keeping the ISBN in its own form, so
+// an ISBN-10 from an old record stays an ISBN-10.
+func (n *ISBN) ToMARC020Original(qualifier string) string {
+	return appendMARCQualifier(n.appendDigits(make([]byte, 0, 32), ""), qualifier)
+}
+
+// appendMARCQualifier appends the qualifier, if any, to a 020 $a
+func appendMARCQualifier(dst []byte, qualifier string) string {
+	q := strings.TrimSpace(qualifier)
+	switch {
+	case q == "":
+		return string(dst)
+	case strings.HasPrefix(q, "(") && strings.HasSuffix(q, ")"),
+		strings.HasPrefix(q, "[") && strings.HasSuffix(q, "]"):
+		return string(dst) + " " + q
+	default:
+		return string(dst) + " (" + q + ")"
+	}
+}
@@ -0,0 +1,61 @@
+package isbn
+
+import (
+	"testing"
+)
+
+func TestExtractFromMARC020(t *testing.T) {
+	cases := []struct {
+		subfield  string
+		isbn      string
+		qualifier string
+		valid     bool
+	}{
+		{"9780804429573", "978-0804429573", "", true},
+		{"9780804429573 (pbk.)", "978-0804429573", "(pbk.)", true},
+		{" 080442957X (v. 1 : alk. paper) ", "080442957X", "(v. 1 : alk. paper)", true},
+		{"0-8044-2957-X [pbk.]", "080442957X", "[pbk.]", true},
+		{"9780804429573(pbk.)", "978-0804429573", "(pbk.)", true},
+		{"(pbk.)", "", "", false},
+		{"", "", "", false},
+		{"9780804429574 (pbk.)", "", "", false},
+	}
+	for _, c := range cases {
+		n, q, err := ExtractFromMARC020(c.subfield)
+		if !c.valid {
+			if err == nil {
+				t.Errorf("Expected `%s` to fail, got %s", c.subfield, n)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Failed to extract from `%s`, error: %s", c.subfield, err)
+			continue
+		}
+		checkStringEqual(t, "Extracted ISBN", n.String(), c.isbn)
+		checkStringEqual(t, "Extracted qualifier", q, c.qualifier)
+	}
+}
+
+func TestToMARC020(t *testing.T) {
+	n, err := Parse("0-8044-2957-X")
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	checkStringEqual(t, "MARC 020", n.ToMARC020(""), "9780804429573")
+	checkStringEqual(t, "MARC 020", n.ToMARC020("pbk."), "9780804429573 (pbk.)")
+	checkStringEqual(t, "MARC 020", n.ToMARC020(" (pbk.) "), "9780804429573 (pbk.)")
+	checkStringEqual(t, "MARC 020", n.ToMARC020("[pbk.]"), "9780804429573 [pbk.]")
+	checkStringEqual(t, "MARC 020 original form", n.ToMARC020Original("v. 1 : alk. paper"), "080442957X (v. 1 : alk. paper)")
+
+	// round trip
+	for _, subfield := range []string{"9780804429573 (pbk.)", "080442957X [v. 1]", "9780804429573"} {
+		n, q, err := ExtractFromMARC020(subfield)
+		if err != nil {
+			t.Fatalf("Failed to extract from `%s`, error: %s", subfield, err)
+		}
+		checkStringEqual(t, "MARC 020 round trip", n.ToMARC020Original(q), subfield)
+	}
+	n, q, _ := ExtractFromMARC020("080442957X (pbk.)")
+	checkStringEqual(t, "MARC 020 upgraded", n.ToMARC020(q), "9780804429573 (pbk.)")
+}
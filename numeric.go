@@ -0,0 +1,185 @@
+package isbn
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+)
+
+const (
+	// largest 13 digit value, any ISBN-13 is below this
+	maxEAN13 = 9999999999999
+	// ISBN-10s are packed above every ISBN-13
+	packed10Base = maxEAN13 + 1
+	// an ISBN-10 body and its base-11 check digit pack below this
+	packed10Span = 1000000000 * 11
+	// there are three ISBN-10 prefix states: none, 978 and 979
+	maxPacked = packed10Base + 3*packed10Span - 1
+)
+
+// EAN13Int returns the ISBN-13 form as a number, e.g. 9780804429573
+func (n *ISBN) EAN13Int() uint64 {
+	n13 := n.To13()
+	var v uint64
+	for _, d := range n13.prefix {
+		v = v*10 + uint64(d)
+	}
+	for _, d := range n13.digits {
+		v = v*10 + uint64(d)
+	}
+	return v*10 + uint64(n13.checksum)
+}
+
+// ParseInt turns a 13 digit number into an ISBN-13, checking the
+// prefix and checksum like Parse.
+func ParseInt(v uint64) (*ISBN, error) {
+	if v > maxEAN13 {
+		return nil, fmt.Errorf("ISBN-13 value out of range: %d", v)
+	}
+	parsed := &ISBN{is13: true}
+	parsed.checksum = byte(v % 10)
+	v /= 10
+	for i := len(parsed.digits) - 1; i >= 0; i-- {
+		parsed.digits[i] = byte(v % 10)
+		v /= 10
+	}
+	for i := len(parsed.prefix) - 1; i >= 0; i-- {
+		parsed.prefix[i] = byte(v % 10)
+		v /= 10
+	}
+	if !isAllowedPrefix(parsed.prefix) {
+		return nil, fmt.Errorf("Unexpected ISBN-13 prefix: %s", digitString(parsed.prefix[:]))
+	}
+	if !parsed.isValid() {
+		return nil, fmt.Errorf("ISBN checksum was incorrect")
+	}
+	return parsed, nil
+}
+
+// Pack returns the ISBN as a single number, which Unpack turns back
+// into exactly the same ISBN. An ISBN-13 packs to its EAN13Int value,
+// an ISBN-10 packs above all of those, as
+// `packed10Base + prefix*packed10Span + body*11 + check`. The check is
+// the last base-11 digit so that X (10) needs no special casing, and
+// prefix is 0 for none, 1 for 978 and 2 for 979 (see To10).
+func (n *ISBN) Pack() uint64 {
+	if n.is13 {
+		return n.EAN13Int()
+	}
+	var p uint64
+	for i := range allowedISBN13Prefixes {
+		if bytes.Equal(n.prefix[:], allowedISBN13Prefixes[i]) {
+			p = uint64(i) + 1
+		}
+	}
+	var body uint64
+	for _, d := range n.digits {
+		body = body*10 + uint64(d)
+	}
+	return packed10Base + p*packed10Span + body*11 + uint64(n.checksum)
+}
+
+// Unpack reverses Pack, validating the result.
+func Unpack(v uint64) (*ISBN, error) {
+	if v < packed10Base {
+		return ParseInt(v)
+	}
+	if v > maxPacked {
+		return nil, fmt.Errorf("Packed ISBN value out of range: %d", v)
+	}
+	v -= packed10Base
+	parsed := &ISBN{}
+	if p := v / packed10Span; p > 0 {
+		copy(parsed.prefix[:], allowedISBN13Prefixes[p-1])
+	}
+	v %= packed10Span
+	parsed.checksum = byte(v % 11)
+	v /= 11
+	for i := len(parsed.digits) - 1; i >= 0; i-- {
+		parsed.digits[i] = byte(v % 10)
+		v /= 10
+	}
+	if !parsed.isValid() {
+		return nil, fmt.Errorf("ISBN checksum was incorrect")
+	}
+	return parsed, nil
+}
+
+// Rank returns the ISBN-13 value as a big.Int, for partitioning
+// ISBNs into ranges. Ranks follow the numeric order of the ISBN-13s,
+// so all 979 ISBNs rank after the 978 ones, and an ISBN-10 ranks as
+// its ISBN-13.
+func (n *ISBN) Rank() *big.Int {
+	return new(big.Int).SetUint64(n.EAN13Int())
+}
+
+// FromRank is the inverse of Rank, validating like ParseInt.
+func FromRank(r *big.Int) (*ISBN, error) {
+	if r == nil || r.Sign() < 0 || !r.IsUint64() {
+		return nil, fmt.Errorf("ISBN rank out of range: %v", r)
+	}
+	return ParseInt(r.Uint64())
+}
+
+// PackedSize is the number of bytes PackTo writes
+const PackedSize = 7
+
+// PackTo writes the ISBN into the first PackedSize bytes of dst, as 14
+// nibbles, high nibble first:
+//
+//	0      flags, 0x1 for ISBN-13 (the other bits are zero)
+//	1-3    the prefix digits (all zero for a parsed ISBN-10, but see To10)
+//	4-12   the body digits
+//	13     the check digit, 0xA for X
+//
+// It returns the number of bytes written.
+func (n *ISBN) PackTo(dst []byte) (int, error) {
+	if len(dst) < PackedSize {
+		return 0, fmt.Errorf("Buffer too short for packed ISBN: %d bytes", len(dst))
+	}
+	var nibbles [PackedSize * 2]byte
+	if n.is13 {
+		nibbles[0] = 1
+	}
+	copy(nibbles[1:4], n.prefix[:])
+	copy(nibbles[4:13], n.digits[:])
+	nibbles[13] = n.checksum
+	for i := 0; i < PackedSize; i++ {
+		dst[i] = nibbles[2*i]<<4 | nibbles[2*i+1]
+	}
+	return PackedSize, nil
+}
+
+// UnpackFrom reads an ISBN written by PackTo from the start of src,
+// validating it, and returns it with the number of bytes read.
+func UnpackFrom(src []byte) (*ISBN, int, error) {
+	if len(src) < PackedSize {
+		return nil, 0, fmt.Errorf("Buffer too short for packed ISBN: %d bytes", len(src))
+	}
+	var nibbles [PackedSize * 2]byte
+	for i := 0; i < PackedSize; i++ {
+		nibbles[2*i], nibbles[2*i+1] = src[i]>>4, src[i]&0xf
+	}
+	if nibbles[0] > 1 {
+		return nil, 0, fmt.Errorf("Invalid packed ISBN flags: %x", nibbles[0])
+	}
+	for i, d := range nibbles[1:13] {
+		if d > 9 {
+			return nil, 0, fmt.Errorf("Invalid packed ISBN digit at nibble %d: %x", i+1, d)
+		}
+	}
+	n := &ISBN{is13: nibbles[0] == 1, checksum: nibbles[13]}
+	copy(n.prefix[:], nibbles[1:4])
+	copy(n.digits[:], nibbles[4:13])
+	switch {
+	case n.checksum > 10 || (n.is13 && n.checksum == 10):
+		return nil, 0, fmt.Errorf("Invalid packed ISBN check digit: %x", n.checksum)
+	case n.is13 && !isAllowedPrefix(n.prefix):
+		return nil, 0, fmt.Errorf("Unexpected ISBN-13 prefix: %s", digitString(n.prefix[:]))
+	case !n.is13 && n.prefix != [3]byte{} && !isAllowedPrefix(n.prefix):
+		return nil, 0, fmt.Errorf("Unexpected ISBN-10 prefix: %s", digitString(n.prefix[:]))
+	case !n.isValid():
+		return nil, 0, fmt.Errorf("ISBN checksum was incorrect")
+	}
+	return n, PackedSize, nil
+}
@@ -0,0 +1,177 @@
+package isbn
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestEAN13Int(t *testing.T) {
+	cases := []struct {
+		isbn string
+		v    uint64
+	}{
+		{"080442957X", 9780804429573},
+		{"9780836220889", 9780836220889},
+		// smallest 978 number
+		{"978-0000000002", 9780000000002},
+		// largest 979 number
+		{"979-9999999990", 9799999999990},
+	}
+	for _, c := range cases {
+		n, err := Parse(c.isbn)
+		if err != nil {
+			t.Errorf("Failed to parse `%s`, error: %s", c.isbn, err)
+			continue
+		}
+		if v := n.EAN13Int(); v != c.v {
+			t.Errorf("EAN13Int of `%s` was %d, expected %d", c.isbn, v, c.v)
+		}
+		back, err := ParseInt(c.v)
+		if err != nil {
+			t.Errorf("Failed ParseInt(%d), error: %s", c.v, err)
+			continue
+		}
+		checkStringEqual(t, "ParseInt should give the ISBN-13", back.String(), n.To13().String())
+	}
+	for _, v := range []uint64{0, 9780000000000, 9770000000004, 9800000000007, maxEAN13 + 1, math.MaxUint64} {
+		if _, err := ParseInt(v); err == nil {
+			t.Errorf("Expected ParseInt(%d) to fail", v)
+		}
+	}
+}
+
+func TestPack(t *testing.T) {
+	n979, err := Parse("979-9999999990")
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	// the largest valid packed value is the ISBN-10 form of the
+	// largest 979 number
+	largest := n979.To10()
+	cases := []*ISBN{n979, largest}
+	for _, s := range []string{"080442957X", "978-0000000002", "0000000000", "9780836220889", test979isbn} {
+		n, err := Parse(s)
+		if err != nil {
+			t.Fatalf("Failed to parse `%s`, error: %s", s, err)
+		}
+		cases = append(cases, n, n.To10(), n.To13())
+	}
+	for _, n := range cases {
+		v := n.Pack()
+		back, err := Unpack(v)
+		if err != nil {
+			t.Errorf("Failed to unpack %d (%s), error: %s", v, n, err)
+			continue
+		}
+		if *back != *n {
+			t.Errorf("Pack round trip of %s gave %s (%v vs %v)", n, back, *n, *back)
+		}
+	}
+	// its body would need a 9 check digit, so the very top of the range
+	// (which would be an X) is invalid
+	if v := largest.Pack(); v != maxPacked-1 {
+		t.Errorf("Expected %s to pack to %d, got %d", largest, uint64(maxPacked-1), v)
+	}
+	for _, v := range []uint64{maxPacked, maxPacked + 1, math.MaxUint64, packed10Base + 1} {
+		if _, err := Unpack(v); err == nil {
+			t.Errorf("Expected Unpack(%d) to fail", v)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	n978, err := Parse("080442957X")
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	n979, err := Parse(test979isbn)
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	if n978.Rank().Cmp(n979.Rank()) >= 0 {
+		t.Errorf("Expected 978 rank %s to sort before 979 rank %s", n978.Rank(), n979.Rank())
+	}
+	checkStringEqual(t, "Rank of an ISBN-10 should be its ISBN-13 value", n978.Rank().String(), "9780804429573")
+	for _, n := range []*ISBN{n978, n979} {
+		back, err := FromRank(n.Rank())
+		if err != nil {
+			t.Errorf("Failed FromRank(%s), error: %s", n.Rank(), err)
+			continue
+		}
+		checkStringEqual(t, "FromRank should give the ISBN-13", back.String(), n.To13().String())
+	}
+	// half way between them is a partition boundary, but not an ISBN
+	mid := new(big.Int).Add(n978.Rank(), n979.Rank())
+	mid.Div(mid, big.NewInt(2))
+	if _, err := FromRank(mid); err == nil {
+		t.Errorf("Expected FromRank(%s) to fail", mid)
+	}
+	huge := new(big.Int).Lsh(big.NewInt(1), 80)
+	for _, r := range []*big.Int{nil, big.NewInt(-1), huge} {
+		if _, err := FromRank(r); err == nil {
+			t.Errorf("Expected FromRank(%v) to fail", r)
+		}
+	}
+}
+
+func TestPackTo(t *testing.T) {
+	var cases []*ISBN
+	for _, s := range []string{"080442957X", "978-0000000002", "0000000000", "979-9999999990", test979isbn} {
+		n, err := Parse(s)
+		if err != nil {
+			t.Fatalf("Failed to parse `%s`, error: %s", s, err)
+		}
+		cases = append(cases, n, n.To10(), n.To13())
+	}
+	for _, n := range cases {
+		buf := make([]byte, PackedSize+1)
+		w, err := n.PackTo(buf)
+		if err != nil || w != PackedSize {
+			t.Errorf("PackTo of %s gave %d, %v", n, w, err)
+			continue
+		}
+		back, r, err := UnpackFrom(buf)
+		if err != nil || r != PackedSize {
+			t.Errorf("UnpackFrom of %s (%x) gave %d, %v", n, buf, r, err)
+			continue
+		}
+		if *back != *n {
+			t.Errorf("PackTo round trip of %v gave %v", *n, *back)
+		}
+	}
+	// the layout
+	n, _ := Parse("080442957X")
+	buf := make([]byte, PackedSize)
+	n.PackTo(buf)
+	checkStringEqual(t, "Packed ISBN-10 layout", fmt.Sprintf("%x", buf), "0000080442957a")
+	n.To13().PackTo(buf)
+	checkStringEqual(t, "Packed ISBN-13 layout", fmt.Sprintf("%x", buf), "19780804429573")
+
+	if _, err := n.PackTo(make([]byte, PackedSize-1)); err == nil {
+		t.Errorf("Expected PackTo to fail on a short buffer")
+	}
+	bad := []string{
+		"",
+		"197808044295",
+		// flags
+		"29780804429573",
+		// X in an ISBN-13 and in a body
+		"1978080442957a",
+		"00000804a2957a",
+		// prefixes
+		"19770804429573",
+		"09770804429573",
+		// checksum
+		"19780804429574",
+		"0000080442957b",
+	}
+	for _, h := range bad {
+		var src []byte
+		fmt.Sscanf(h, "%x", &src)
+		if n, _, err := UnpackFrom(src); err == nil {
+			t.Errorf("Expected UnpackFrom(%s) to fail, got %v", h, *n)
+		}
+	}
+}
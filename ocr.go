@@ -0,0 +1,36 @@
+package isbn
+
+// ocrConfusions lists the digit pairs that OCR engines commonly
+// mistake for each other: 0/8, 1/7, 3/8, 5/6 and 4/9.
+// The relation is symmetric.
+var ocrConfusions = [10][10]bool{}
+
+func init() {
+	for _, p := range [][2]byte{{0, 8}, {1, 7}, {3, 8}, {5, 6}, {4, 9}} {
+		ocrConfusions[p[0]][p[1]] = true
+		ocrConfusions[p[1]][p[0]] = true
+	}
+}
+
+// AreOCRConfusable checks whether the ISBN-13 forms of a and b differ
+// only by common OCR misreads (see ocrConfusions), i.e. they could be
+// the same printed ISBN scanned two different ways.
+// The check digit is ignored as it follows from the other digits, and
+// identical ISBNs are trivially confusable.
+func AreOCRConfusable(a, b *ISBN) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	a, b = a.To13(), b.To13()
+	for i, d := range a.prefix {
+		if d != b.prefix[i] && !ocrConfusions[d][b.prefix[i]] {
+			return false
+		}
+	}
+	for i, d := range a.digits {
+		if d != b.digits[i] && !ocrConfusions[d][b.digits[i]] {
+			return false
+		}
+	}
+	return true
+}
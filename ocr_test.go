@@ -0,0 +1,40 @@
+package isbn
+
+import (
+	"testing"
+)
+
+func TestAreOCRConfusable(t *testing.T) {
+	cases := []struct {
+		a, b       string
+		confusable bool
+	}{
+		// identical, and the same book in both forms
+		{"9780836220889", "9780836220889", true},
+		{"0836220889", "9780836220889", true},
+		// 0836218353 vs 0836218833: 3/8 is a confusion, but 5/3 is not
+		{"0836218353", "0836218833", false},
+		// 0/8 and 7/1 confusions in the body (the check digits differ)
+		{"9780836218787", "9788836218189", true},
+		// 2/3 is not a confusion
+		{"9780836220889", "9780836320886", false},
+	}
+	for _, c := range cases {
+		a, err := Parse(c.a)
+		if err != nil {
+			t.Errorf("Failed to parse `%s`, error: %s", c.a, err)
+			continue
+		}
+		b, err := Parse(c.b)
+		if err != nil {
+			t.Errorf("Failed to parse `%s`, error: %s", c.b, err)
+			continue
+		}
+		if got := AreOCRConfusable(a, b); got != c.confusable {
+			t.Errorf("AreOCRConfusable(%s, %s) = %v, expected %v", c.a, c.b, got, c.confusable)
+		}
+	}
+	if AreOCRConfusable(nil, nil) {
+		t.Errorf("nil ISBNs should not be confusable")
+	}
+}
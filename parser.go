@@ -0,0 +1,265 @@
+package isbn
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Parser parses ISBNs with options that Parse does not allow.
+// The zero value parses exactly like Parse.
+type Parser struct {
+	// AllowAltCheckGlyphs accepts a final `*` or `10` as the check
+	// digit X of an ISBN-10, as some legacy systems print it. The
+	// checksum is validated as normal.
+	AllowAltCheckGlyphs bool
+
+	// set by WithMaxSeparators, otherwise the legacy rule applies
+	limitSeparators bool
+	maxSeparators   int
+}
+
+// ParserOption configures a Parser made by NewParser.
+type ParserOption func(*Parser)
+
+// NewParser makes a Parser with the options applied. With no options
+// it parses exactly like Parse.
+func NewParser(opts ...ParserOption) *Parser {
+	p := &Parser{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// maxParserSeparators is the most separators WithMaxSeparators allows,
+// far more than even one between every digit, and small enough that
+// the length limits it gives cannot overflow
+const maxParserSeparators = 64
+
+// WithMaxSeparators replaces the default limit of 13 characters plus
+// 4, separators or not, with at most n separators (see isSeparator).
+// Other stripped characters still count toward 13 plus n. A Parser
+// with n of 0 only accepts ISBNs without separators, and a negative n
+// is treated as 0. An n above maxParserSeparators is treated as it, so
+// a huge n (e.g. math.MaxInt) means no practical limit.
+func WithMaxSeparators(n int) ParserOption {
+	return func(p *Parser) {
+		if n < 0 {
+			n = 0
+		}
+		if n > maxParserSeparators {
+			n = maxParserSeparators
+		}
+		p.limitSeparators, p.maxSeparators = true, n
+	}
+}
+
+// Parse turns a string into an ISBN like the package level Parse,
+// subject to the parser options.
+func (p *Parser) Parse(s string) (*ISBN, error) {
+	maxSeparators := legacySeparators
+	if p.limitSeparators {
+		maxSeparators = p.maxSeparators
+	}
+	if p.AllowAltCheckGlyphs {
+		s = altCheckGlyphs(s, maxSeparators)
+	}
+	parsed := &ISBN{}
+	if err := parseDigitsMax(s, parsed, maxSeparators); err != nil {
+		return nil, err
+	}
+	if !parsed.isValid() {
+		return nil, fmt.Errorf("ISBN checksum was incorrect")
+	}
+	return parsed, nil
+}
+
+// altCheckGlyphs rewrites a trailing `*`, or a trailing `10` on what
+// would otherwise be an ISBN-10 with one digit too many, as X.
+func altCheckGlyphs(s string, maxSeparators int) string {
+	if maxSeparators == legacySeparators {
+		maxSeparators = 4
+	}
+	if len(s) > len(urnPrefix)+13+maxSeparators*maxSeparatorLen {
+		// Parse will reject it anyway
+		return s
+	}
+	if strings.HasSuffix(s, "*") {
+		return s[:len(s)-1] + "X"
+	}
+	if strings.HasSuffix(s, "10") && len(strings.Map(runeToISBNDigit, s)) == 11 {
+		return s[:len(s)-2] + "X"
+	}
+	return s
+}
+
+// Scheme is the ISBN-10 checksum algorithm used by ParseWithScheme.
+type Scheme int
+
+const (
+	// SchemeStandard is the ISBN-10 checksum as specified, weighting
+	// the digits 10..1 so the sum is 0 mod 11.
+	SchemeStandard Scheme = iota
+	// SchemeAscending is NOT standard. Some legacy systems weight the
+	// body digits 1..9 and then take 11 minus the remainder, as the
+	// standard algorithm does, which gives a different check digit.
+	// (Weighting 1..10 and requiring a sum of 0 mod 11 is equivalent to
+	// the standard scheme.) Only use it to ingest such data.
+	SchemeAscending
+)
+
+// ParseWithScheme parses like Parse, but validates the checksum of an
+// ISBN-10 with the given scheme. The ISBN returned always has the
+// standard check digit, so legacy data is normalised on output.
+// ISBN-13 checksums are always standard.
+func ParseWithScheme(s string, scheme Scheme) (*ISBN, error) {
+	if scheme != SchemeStandard && scheme != SchemeAscending {
+		return nil, fmt.Errorf("Unknown ISBN checksum scheme: %d", scheme)
+	}
+	parsed := &ISBN{}
+	if err := parseDigits(s, parsed); err != nil {
+		return nil, err
+	}
+	if scheme == SchemeAscending && !parsed.is13 {
+		if checkAscending10(parsed.digits) != parsed.checksum {
+			return nil, fmt.Errorf("ISBN checksum was incorrect")
+		}
+		parsed.checksum = check10(parsed.digits)
+	} else if !parsed.isValid() {
+		return nil, fmt.Errorf("ISBN checksum was incorrect")
+	}
+	return parsed, nil
+}
+
+// checkAscending10 returns the SchemeAscending check digit value
+func checkAscending10(digits [9]byte) byte {
+	sum := 0
+	for i, d := range digits {
+		sum += int(d) * (i + 1)
+	}
+	m := sum % 11
+	if m == 0 {
+		return 0
+	}
+	return byte(11 - m)
+}
+
+// InputForm records how the input to ParseTyped was written
+type InputForm int
+
+const (
+	// FormUnknown is returned with parse errors
+	FormUnknown InputForm = iota
+	// Form10 is a plain ISBN-10, e.g. `0-8044-2957-X`
+	Form10
+	// Form13 is a plain ISBN-13, e.g. `978-0-8044-2957-3`
+	Form13
+	// FormURN10 is an ISBN-10 urn, e.g. `urn:isbn:080442957X`
+	FormURN10
+	// FormURN13 is an ISBN-13 urn, e.g. `urn:isbn:9780804429573`
+	FormURN13
+	// FormLabeled is an ISBN written after a label that Parse strips,
+	// e.g. `ISBN 080442957X`, of either length
+	FormLabeled
+)
+
+// ParseTyped parses exactly like Parse, and also reports the form the
+// input was written in, to keep its provenance.
+func ParseTyped(s string) (*ISBN, InputForm, error) {
+	n, err := Parse(s)
+	if err != nil {
+		return nil, FormUnknown, err
+	}
+	urn := strings.HasPrefix(s, urnPrefix)
+	switch {
+	case !urn && isLabeled(s):
+		return n, FormLabeled, nil
+	case urn && n.is13:
+		return n, FormURN13, nil
+	case urn:
+		return n, FormURN10, nil
+	case n.is13:
+		return n, Form13, nil
+	default:
+		return n, Form10, nil
+	}
+}
+
+// isLabeled reports whether anything other than separators and
+// whitespace comes before the first digit of s
+func isLabeled(s string) bool {
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			return false
+		}
+		if !isSeparator(r) && !unicode.IsSpace(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// URNComponents are the optional RFC 8141 components of a urn, e.g.
+// `urn:isbn:9780804429573?+r?=q#f`, without their `?+`, `?=` and `#`
+// markers.
+type URNComponents struct {
+	R, Q, F string
+}
+
+// ParseURNParams parses like Parse, and also returns the components of
+// a urn input. Parse itself ignores them.
+func ParseURNParams(s string) (*ISBN, URNComponents, error) {
+	var c URNComponents
+	if strings.HasPrefix(s, urnPrefix) {
+		_, c = splitURNComponents(s[len(urnPrefix):])
+	}
+	n, err := Parse(s)
+	if err != nil {
+		return nil, URNComponents{}, err
+	}
+	return n, c, nil
+}
+
+// splitURNComponents splits the components off the namespace specific
+// string of a urn. They are in the order r, q, f, and each is optional.
+func splitURNComponents(nss string) (string, URNComponents) {
+	var c URNComponents
+	if i := strings.IndexByte(nss, '#'); i != -1 {
+		nss, c.F = nss[:i], nss[i+1:]
+	}
+	if i := strings.Index(nss, "?="); i != -1 {
+		nss, c.Q = nss[:i], nss[i+2:]
+	}
+	if i := strings.Index(nss, "?+"); i != -1 {
+		nss, c.R = nss[:i], nss[i+2:]
+	}
+	return nss, c
+}
+
+// ErrUnexpectedChar is the error ParseStrictChars wraps when the input
+// has a character that is neither part of the ISBN nor a separator.
+var ErrUnexpectedChar = errors.New("Unexpected character in ISBN")
+
+// ParseStrictChars parses like Parse, but rather than silently
+// stripping any character that is not a digit or X, it only strips
+// the urn prefix and components and separators (see isSeparator). A
+// stray letter, e.g. `978q0836220889`, is an error wrapping
+// ErrUnexpectedChar even when the digits left are a valid ISBN.
+func ParseStrictChars(s string) (*ISBN, error) {
+	n, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	body := s
+	if strings.HasPrefix(body, urnPrefix) {
+		body, _ = splitURNComponents(body[len(urnPrefix):])
+	}
+	for _, r := range body {
+		if runeToISBNDigit(r) == -1 && !isSeparator(r) {
+			return nil, fmt.Errorf("%w: %q", ErrUnexpectedChar, r)
+		}
+	}
+	return n, nil
+}
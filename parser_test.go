@@ -0,0 +1,259 @@
+package isbn
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParserAltCheckGlyphs(t *testing.T) {
+	cases := []struct {
+		input string
+		isbn  string
+		valid bool
+	}{
+		{"080442957*", "080442957X", true},
+		{"0-8044-2957-*", "080442957X", true},
+		{"urn:isbn:080442957*", "080442957X", true},
+		{"08044295710", "080442957X", true},
+		{"0-8044-2957-10", "080442957X", true},
+		// X still works
+		{"080442957X", "080442957X", true},
+		// the checksum is still checked
+		{"083622088*", "", false},
+		{"08362208810", "", false},
+		// a 13 digit string ending in 10 is not affected
+		{"9781449407100", "978-1449407100", true},
+		// nor is an ISBN-10 ending in 0 with an extra digit
+		{"144940710", "", false},
+		// * is only the ISBN-10 check digit
+		{"978080442957*", "", false},
+	}
+	lenient := &Parser{AllowAltCheckGlyphs: true}
+	for _, c := range cases {
+		n, err := lenient.Parse(c.input)
+		if !c.valid {
+			if err == nil {
+				t.Errorf("Incorrect parsed: %s", c.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Failed to parse `%s`, error: %s", c.input, err)
+			continue
+		}
+		checkStringEqual(t, "Parsed ISBN", n.String(), c.isbn)
+	}
+	// the default parser only accepts X
+	for _, s := range []string{"080442957*", "08044295710"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Incorrect parsed: %s", s)
+		}
+		if _, err := (&Parser{}).Parse(s); err == nil {
+			t.Errorf("Incorrect parsed with the zero Parser: %s", s)
+		}
+	}
+}
+
+func TestParseWithScheme(t *testing.T) {
+	cases := []struct {
+		input  string
+		scheme Scheme
+		isbn   string
+		valid  bool
+	}{
+		{"080442957X", SchemeStandard, "080442957X", true},
+		{"0804429571", SchemeStandard, "", false},
+		// the same books, with ascending checksums, normalised on output
+		{"0804429571", SchemeAscending, "080442957X", true},
+		{"0836220882", SchemeAscending, "0836220889", true},
+		{"0836218255", SchemeAscending, "0836218256", true},
+		{"1-4494-0710-9", SchemeAscending, "1449407102", true},
+		// standard checksums are wrong in the ascending scheme...
+		{"080442957X", SchemeAscending, "", false},
+		{"0836220889", SchemeAscending, "", false},
+		// ...unless the check happens to be 0 in both
+		{"0000000000", SchemeAscending, "0000000000", true},
+		// ISBN-13 is always standard
+		{"9780804429573", SchemeAscending, "978-0804429573", true},
+		{"080442957X", Scheme(42), "", false},
+		{"9780804429573", Scheme(42), "", false},
+	}
+	for _, c := range cases {
+		n, err := ParseWithScheme(c.input, c.scheme)
+		if !c.valid {
+			if err == nil {
+				t.Errorf("Incorrect parsed with scheme %d: %s", c.scheme, c.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Failed to parse `%s` with scheme %d, error: %s", c.input, c.scheme, err)
+			continue
+		}
+		checkStringEqual(t, "Parsed ISBN", n.String(), c.isbn)
+	}
+}
+
+func TestParseTyped(t *testing.T) {
+	cases := []struct {
+		input string
+		form  InputForm
+	}{
+		{"080442957X", Form10},
+		{"0-8044-2957-x", Form10},
+		{"9780804429573", Form13},
+		{"978-0-8044-2957-3", Form13},
+		{"urn:isbn:080442957X", FormURN10},
+		{"urn:isbn:0-8-0-4-42957x", FormURN10},
+		{"urn:isbn:9780804429573", FormURN13},
+		{"urn:isbn:97 808 0442 9573", FormURN13},
+		{"ISBN 080442957X", FormLabeled},
+		{"ISBN9780804429573", FormLabeled},
+		{"isbn:0-8044-2957X", FormLabeled},
+		{" - 080442957X", Form10},
+		{"\t080442957X", Form10},
+		{"\u00a0080442957X", Form10},
+		{"badformat!", FormUnknown},
+		{"urn:isbn:9780804429574", FormUnknown},
+	}
+	for _, c := range cases {
+		n, form, err := ParseTyped(c.input)
+		if form != c.form {
+			t.Errorf("ParseTyped(%s) form was %d, expected %d", c.input, form, c.form)
+		}
+		expected, perr := Parse(c.input)
+		if (err == nil) != (perr == nil) {
+			t.Errorf("ParseTyped(%s) error %v does not match Parse error %v", c.input, err, perr)
+			continue
+		}
+		if err == nil && *n != *expected {
+			t.Errorf("ParseTyped(%s) gave %s, Parse gave %s", c.input, n, expected)
+		}
+	}
+}
+
+func TestParseURNParams(t *testing.T) {
+	cases := []struct {
+		input      string
+		isbn       string
+		components URNComponents
+		valid      bool
+	}{
+		{"urn:isbn:978-0-8044-2957-3", "978-0804429573", URNComponents{}, true},
+		{"urn:isbn:978-0-8044-2957-3#frag", "978-0804429573", URNComponents{F: "frag"}, true},
+		{"urn:isbn:978-0-8044-2957-3?=foo", "978-0804429573", URNComponents{Q: "foo"}, true},
+		{"urn:isbn:080442957X?+res?=a=b&c=d#page-1", "080442957X", URNComponents{"res", "a=b&c=d", "page-1"}, true},
+		// these are only urn components
+		{"978-0-8044-2957-3#frag", "", URNComponents{}, false},
+		{"urn:isbn:978-0-8044-2957-4#frag", "", URNComponents{}, false},
+		{"urn:isbn:#frag", "", URNComponents{}, false},
+	}
+	for _, c := range cases {
+		n, components, err := ParseURNParams(c.input)
+		if !c.valid {
+			if err == nil {
+				t.Errorf("Incorrect parsed: %s", c.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Failed to parse `%s`, error: %s", c.input, err)
+			continue
+		}
+		checkStringEqual(t, "Parsed ISBN", n.String(), c.isbn)
+		if components != c.components {
+			t.Errorf("Components of `%s` were %+v, expected %+v", c.input, components, c.components)
+		}
+		// Parse accepts them too
+		if _, err := Parse(c.input); err != nil {
+			t.Errorf("Failed to Parse `%s`, error: %s", c.input, err)
+		}
+	}
+}
+
+func TestParserMaxSeparators(t *testing.T) {
+	cases := []struct {
+		input string
+		max   int
+		valid bool
+	}{
+		{"9780804429573", 0, true},
+		{"978-0804429573", 0, false},
+		{"080442957X", 0, true},
+		{"0-8044-2957-X", 0, false},
+		{"978-0-8044-2957-3", 4, true},
+		{"97-8-0-8044-2957-3", 4, false},
+		// the legacy rule allows 7 separators in an ISBN-10
+		{"0-8-0-4-4-2957-X", 4, false},
+		{"0-8-0-4-4-2957-X", 7, true},
+		{"9-7-8-0-8-0-4-4-2-9-5-7-3", 12, true},
+		{"9-7-8-0-8-0-4-4-2-9-5-7-3", 11, false},
+		{"9\u00b77\u00b78\u00b70\u00b78\u00b70\u00b74\u00b74\u00b72\u00b79\u00b75\u00b77\u00b73", 12, true},
+		{"urn:isbn:9-7-8-0-8-0-4-4-2-9-5-7-3", 12, true},
+		// other characters still count toward the limit
+		{"978-0804429573.", 1, false},
+		{"978-0804429573.", 2, true},
+		// negative is no separators
+		{"978-0804429573", -1, false},
+		{"9780804429573", -1, true},
+		// huge limits are no practical limit, without overflowing
+		{"9780804429573", math.MaxInt, true},
+		{"9780804429573", math.MaxInt / 2, true},
+		{"9780804429573", math.MaxInt / maxSeparatorLen, true},
+		{"--9-7-8-0-8-0-4-4-2-9-5-7-3--", math.MaxInt, true},
+		{strings.Repeat("-", maxParserSeparators) + "9780804429573", math.MaxInt, true},
+		{strings.Repeat("-", maxParserSeparators+1) + "9780804429573", math.MaxInt, false},
+	}
+	for _, c := range cases {
+		_, err := NewParser(WithMaxSeparators(c.max)).Parse(c.input)
+		if c.valid && err != nil {
+			t.Errorf("Failed to parse `%s` with at most %d separators, error: %s", c.input, c.max, err)
+		} else if !c.valid && err == nil {
+			t.Errorf("Incorrect parsed with at most %d separators: %s", c.max, c.input)
+		}
+	}
+	// with no options, and as the zero value, the legacy rule applies
+	for _, p := range []*Parser{NewParser(), {}} {
+		for _, v := range tests {
+			for _, s := range []string{v.isbn10, v.isbn13} {
+				if _, err := p.Parse(s); (err == nil) != Validate(s) {
+					t.Errorf("Expected the default Parser to parse `%s` like Parse", s)
+				}
+			}
+		}
+	}
+	lenient := NewParser(WithMaxSeparators(6))
+	lenient.AllowAltCheckGlyphs = true
+	if n, err := lenient.Parse("0-8-0-4-4-2957*"); err != nil || n.String() != "080442957X" {
+		t.Errorf("Expected the options to combine, got %s, %v", n, err)
+	}
+}
+
+func TestParseStrictChars(t *testing.T) {
+	for _, s := range []string{
+		"9780836220889",
+		"978-0-8362-2088-9",
+		"978 0\u00b78362\u20092088-9",
+		"urn:isbn:978-0836220889",
+		"urn:isbn:978-0836220889?=q#f",
+		"080442957x",
+	} {
+		if _, err := ParseStrictChars(s); err != nil {
+			t.Errorf("Failed to strictly parse `%s`, error: %s", s, err)
+		}
+	}
+	for _, s := range []string{"978q0836220889", "0836z220889", "978-0836220889.", "isbn9780836220889"} {
+		if _, err := Parse(s); err != nil {
+			t.Fatalf("Expected `%s` to parse leniently, error: %s", s, err)
+		}
+		if _, err := ParseStrictChars(s); !errors.Is(err, ErrUnexpectedChar) {
+			t.Errorf("Expected ErrUnexpectedChar for `%s`, got %v", s, err)
+		}
+	}
+	// other errors are Parse's
+	if _, err := ParseStrictChars("978q0836220888"); err == nil || errors.Is(err, ErrUnexpectedChar) {
+		t.Errorf("Expected a checksum error, got %v", err)
+	}
+}
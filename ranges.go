@@ -0,0 +1,274 @@
+package isbn
+
+import (
+	"fmt"
+)
+
+// rangeRule gives the length of the next element of an ISBN for the
+// 7 digit windows between low and high.
+// The bounds are 7 digit strings, so they compare lexically.
+// A length of 0 means the range is not defined (yet), and
+// rangeNotCarried that it is defined but not in these tables.
+type rangeRule struct {
+	low, high string
+	length    int
+}
+
+// rangeNotCarried is the length of a span whose finer ranges in
+// RangeMessage.xml are not carried here
+const rangeNotCarried = -1
+
+// These tables are a subset of the International ISBN Agency's
+// RangeMessage.xml, https://www.isbn-international.org/range_file_generation
+// ISBNs in registration groups not listed here cannot be segmented.
+// Nor can those in the spans of a listed group marked rangeNotCarried,
+// where RangeMessage.xml has many small ranges of varying registrant
+// lengths; segmenting them is an error rather than a guess.
+
+// registration group lengths by GS1 prefix
+var groupRules = map[string][]rangeRule{
+	"978": {
+		{"0000000", "5999999", 1},
+		{"6000000", "6499999", 3},
+		{"6500000", "6599999", 2},
+		{"6600000", "6999999", 0},
+		{"7000000", "7999999", 1},
+		{"8000000", "9499999", 2},
+		{"9500000", "9899999", 3},
+		{"9900000", "9989999", 4},
+		{"9990000", "9999999", 5},
+	},
+	"979": {
+		{"0000000", "0999999", 0},
+		{"1000000", "1299999", 2},
+		{"1300000", "7999999", 0},
+		{"8000000", "8999999", 1},
+		{"9000000", "9999999", 0},
+	},
+}
+
+// registrant lengths by GS1 prefix and registration group
+var registrantRules = map[string][]rangeRule{
+	// English language
+	"978-0": {
+		{"0000000", "1999999", 2},
+		{"2000000", "6999999", rangeNotCarried},
+		{"7000000", "8499999", 4},
+		{"8500000", "8999999", 5},
+		{"9000000", "9499999", 6},
+		{"9500000", "9999999", 7},
+	},
+	"978-1": {
+		{"0000000", "0999999", 2},
+		{"1000000", "3999999", 3},
+		{"4000000", "5499999", 4},
+		{"5500000", "9999999", rangeNotCarried},
+	},
+	// French language
+	"978-2": {
+		{"0000000", "1999999", 2},
+		{"2000000", "3499999", 3},
+		{"3500000", "3999999", 5},
+		{"4000000", "6999999", rangeNotCarried},
+		{"7000000", "8399999", 4},
+		{"8400000", "8999999", 5},
+		{"9000000", "9499999", rangeNotCarried},
+		{"9500000", "9999999", 7},
+	},
+	// German language
+	"978-3": {
+		{"0000000", "0299999", 2},
+		{"0300000", "0339999", 3},
+		{"0340000", "0369999", 4},
+		{"0370000", "0399999", 5},
+		{"0400000", "1999999", 2},
+		{"2000000", "6999999", 3},
+		{"7000000", "8499999", 4},
+		{"8500000", "8999999", 5},
+		{"9000000", "9499999", 6},
+		{"9500000", "9539999", 7},
+		{"9540000", "9699999", 5},
+		{"9700000", "9999999", rangeNotCarried},
+	},
+	// France
+	"979-10": {
+		{"0000000", "1999999", 2},
+		{"2000000", "6999999", 3},
+		{"7000000", "8999999", 4},
+		{"9000000", "9759999", 5},
+		{"9760000", "9999999", 6},
+	},
+	// Italy
+	"979-12": {
+		{"0000000", "1999999", 0},
+		{"2000000", "2999999", 3},
+		{"3000000", "5449999", 0},
+		{"5450000", "5999999", 4},
+		{"6000000", "7999999", 0},
+		{"8000000", "8499999", 5},
+		{"8500000", "9849999", 0},
+		{"9850000", "9999999", 6},
+	},
+}
+
+// segments holds the elements of a hyphenated ISBN, as digit strings
+type segments struct {
+	prefix      string
+	group       string
+	registrant  string
+	publication string
+	check       string
+}
+
+// ruleLength finds the element length for the start of s in rules.
+// s is padded with zeros to the 7 digit window the rules use.
+func ruleLength(rules []rangeRule, s string) int {
+	for len(s) < 7 {
+		s += "0"
+	}
+	w := s[:7]
+	for _, r := range rules {
+		if w >= r.low && w <= r.high {
+			return r.length
+		}
+	}
+	return 0
+}
+
+// segments splits the ISBN into prefix, group, registrant, publication
+// and check digit using the range tables. The prefix is empty for
+// ISBN-10.
+func (n *ISBN) segments() (segments, error) {
+	if n.is979Form10() {
+		return segments{}, fmt.Errorf("ISBN-10 form of a %s prefixed ISBN cannot be segmented", digitString(n.prefix[:]))
+	}
+	prefix, body, g, r, err := n.rangeLengths()
+	if err != nil {
+		return segments{}, err
+	}
+	if r == 0 {
+		return segments{}, fmt.Errorf("ISBN registrant range is not defined for %s", n.To13())
+	}
+	seg := segments{
+		prefix:      prefix,
+		group:       body[:g],
+		registrant:  body[g : g+r],
+		publication: body[g+r:],
+		check:       string(isbnDigitToByte(n.checksum)),
+	}
+	if !n.is13 {
+		seg.prefix = ""
+	}
+	return seg, nil
+}
+
+// rangeLengths looks up the lengths of the group and registrant of the
+// ISBN-13 form, which has the given prefix and body digits. It is an
+// error if the group is unknown, but a registrant length of 0 means
+// the group has no registrants in that range. It is also an error if
+// the range is one the tables do not carry.
+func (n *ISBN) rangeLengths() (prefix, body string, g, r int, err error) {
+	n13 := n.To13()
+	prefix = digitString(n13.prefix[:])
+	body = digitString(n13.digits[:])
+	g = ruleLength(groupRules[prefix], body)
+	if g == 0 {
+		return "", "", 0, 0, fmt.Errorf("ISBN registration group is unknown for %s", n13)
+	}
+	rules, ok := registrantRules[prefix+"-"+body[:g]]
+	if !ok {
+		return "", "", 0, 0, fmt.Errorf("ISBN range data is not available for group %s-%s", prefix, body[:g])
+	}
+	r = ruleLength(rules, body[g:])
+	if r == rangeNotCarried {
+		return "", "", 0, 0, fmt.Errorf("ISBN range data is not available for %s", n13)
+	}
+	if g+r >= len(body) {
+		// there must be room for a publication element
+		r = 0
+	}
+	return prefix, body, g, r, nil
+}
+
+// RegistrantRangeAssigned checks whether the registrant of the ISBN
+// falls in a range its registration group has defined, rather than in
+// a gap that is structurally possible but unassigned. It is an error
+// if the group, or the span of the group it is in, is not in the range
+// data.
+func (n *ISBN) RegistrantRangeAssigned() (bool, error) {
+	_, _, _, r, err := n.rangeLengths()
+	if err != nil {
+		return false, err
+	}
+	return r != 0, nil
+}
+
+// is979Form10 reports whether this is the ISBN-10 form of a 979 ISBN
+// (see To10), which is not a real ISBN-10.
+func (n *ISBN) is979Form10() bool {
+	return !n.is13 && n.prefix != [3]byte{} && n.prefix != [3]byte{9, 7, 8}
+}
+
+// digitString turns isbn digit values into their string form
+func digitString(digits []byte) string {
+	b := make([]byte, len(digits))
+	for i, d := range digits {
+		b[i] = isbnDigitToByte(d)
+	}
+	return string(b)
+}
+
+// hyphenated joins the segments with hyphens, e.g. `978-0-8044-2957-3`
+func (n *ISBN) hyphenated() (string, error) {
+	seg, err := n.segments()
+	if err != nil {
+		return "", err
+	}
+	s := seg.group + "-" + seg.registrant + "-" + seg.publication + "-" + seg.check
+	if seg.prefix != "" {
+		s = seg.prefix + "-" + s
+	}
+	return s, nil
+}
+
+// InPublisherBlock checks whether n belongs to the publisher block from
+// low to high: all three must share the prefix, group and registrant,
+// and the publication element of n must be between those of low and
+// high. Check digits don't matter, and ISBN-10s are compared as their
+// ISBN-13 forms. ISBNs the range data cannot segment are in no block.
+func InPublisherBlock(n, low, high *ISBN) bool {
+	if n == nil || low == nil || high == nil {
+		return false
+	}
+	seg, err := n.To13().segments()
+	if err != nil {
+		return false
+	}
+	lo, err := low.To13().segments()
+	if err != nil {
+		return false
+	}
+	hi, err := high.To13().segments()
+	if err != nil {
+		return false
+	}
+	for _, b := range []segments{lo, hi} {
+		if b.prefix != seg.prefix || b.group != seg.group || b.registrant != seg.registrant {
+			return false
+		}
+	}
+	// same registrant, so the publication elements are the same length
+	return lo.publication <= seg.publication && seg.publication <= hi.publication
+}
+
+// PublisherCode returns the group and registrant, e.g. `0-8044`, which
+// is how the trade refers to a publisher. Unlike the full hyphenated
+// form it has no GS1 prefix, so a 979 publisher code is ambiguous with
+// a 978 one.
+func (n *ISBN) PublisherCode() (string, error) {
+	seg, err := n.To13().segments()
+	if err != nil {
+		return "", err
+	}
+	return seg.group + "-" + seg.registrant, nil
+}
@@ -0,0 +1,176 @@
+package isbn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSegments(t *testing.T) {
+	cases := []struct {
+		isbn     string
+		expected segments
+		valid    bool
+	}{
+		{"9780804429573", segments{"978", "0", "8044", "2957", "3"}, true},
+		{"080442957X", segments{"", "0", "8044", "2957", "X"}, true},
+		{"9781449407100", segments{"978", "1", "4494", "0710", "0"}, true},
+		{"0836220889", segments{"", "0", "8362", "2088", "9"}, true},
+		// 979-5 is not a registration group
+		{test979isbn, segments{}, false},
+		// 978-0 6398000-6399999 are 7 digit registrants in a span of
+		// mostly 3 digit ones, which is not carried
+		{withCheck13("978-0-6398000-0-?"), segments{}, false},
+		{withCheck13("978-0-645-00000-?"), segments{}, false},
+	}
+	for _, c := range cases {
+		n, err := Parse(c.isbn)
+		if err != nil {
+			t.Errorf("Failed to parse `%s`, error: %s", c.isbn, err)
+			continue
+		}
+		seg, err := n.segments()
+		if !c.valid {
+			if err == nil {
+				t.Errorf("Expected `%s` not to be segmented, got %v", c.isbn, seg)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Failed to segment `%s`, error: %s", c.isbn, err)
+			continue
+		}
+		if seg != c.expected {
+			t.Errorf("Segments of `%s` were %v, expected %v", c.isbn, seg, c.expected)
+		}
+	}
+}
+
+func TestInPublisherBlock(t *testing.T) {
+	parse := func(s string) *ISBN {
+		n, err := Parse(s)
+		if err != nil {
+			t.Fatalf("Failed to parse `%s`, error: %s", s, err)
+		}
+		return n
+	}
+	low, high := parse("978-0-8044-0000-8"), parse("978-0-8044-9999-6")
+	cases := []struct {
+		isbn    string
+		inBlock bool
+	}{
+		{"978-0-8044-2957-3", true},
+		{"080442957X", true},
+		{"978-0-8044-0000-8", true},
+		{"978-0-8044-9999-6", true},
+		// another registrant
+		{"978-0-8045-2957-0", false},
+		{"0836220889", false},
+		// unknown range
+		{test979isbn, false},
+	}
+	for _, c := range cases {
+		if got := InPublisherBlock(parse(c.isbn), low, high); got != c.inBlock {
+			t.Errorf("InPublisherBlock(%s) = %v, expected %v", c.isbn, got, c.inBlock)
+		}
+	}
+	// a narrower block, bounded by publication number
+	low, high = parse("978-0-8044-2000-6"), parse("978-0-8044-2500-1")
+	if !InPublisherBlock(parse("978-0-8044-2000-6"), low, high) {
+		t.Errorf("Expected the low bound to be in the block")
+	}
+	if InPublisherBlock(parse("978-0-8044-2957-3"), low, high) {
+		t.Errorf("Expected a publication above the high bound not to be in the block")
+	}
+	if InPublisherBlock(nil, low, high) {
+		t.Errorf("Expected nil not to be in the block")
+	}
+}
+
+func TestPublisherCode(t *testing.T) {
+	cases := []struct {
+		isbn  string
+		code  string
+		valid bool
+	}{
+		{"080442957X", "0-8044", true},
+		{"9780804429573", "0-8044", true},
+		{"9781449407100", "1-4494", true},
+		{"979-10-90636-07-1", "10-90636", true},
+		{test979isbn, "", false},
+	}
+	for _, c := range cases {
+		n, err := Parse(c.isbn)
+		if err != nil {
+			t.Errorf("Failed to parse `%s`, error: %s", c.isbn, err)
+			continue
+		}
+		code, err := n.PublisherCode()
+		if !c.valid {
+			if err == nil {
+				t.Errorf("Expected PublisherCode of `%s` to fail, got %s", c.isbn, code)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Failed PublisherCode of `%s`, error: %s", c.isbn, err)
+			continue
+		}
+		checkStringEqual(t, "Publisher code", code, c.code)
+	}
+}
+
+func TestRegistrantRangeAssigned(t *testing.T) {
+	cases := []struct {
+		isbn     string
+		assigned bool
+		valid    bool
+	}{
+		{"080442957X", true, true},
+		{"979-10-90636-07-1", true, true},
+		// 979-12: 200-299 is assigned, 300-5449 is not
+		{"979-12-200-0000-?", true, true},
+		{"979-12-3000-000-?", false, true},
+		{"979-12-8000-000-?", true, true},
+		// 979-5 is not a group
+		{test979isbn, false, false},
+		// 978-4 is a group, but not in the range data
+		{"978-4-00-000000-?", false, false},
+		// ranges of listed groups the tables do not carry
+		{"978-0-6398000-0-?", false, false},
+		{"978-1-86980-000-?", false, false},
+		{"978-2-487-00000-?", false, false},
+	}
+	for _, c := range cases {
+		n, err := Parse(withCheck13(c.isbn))
+		if err != nil {
+			t.Errorf("Failed to parse `%s`, error: %s", c.isbn, err)
+			continue
+		}
+		assigned, err := n.RegistrantRangeAssigned()
+		if !c.valid {
+			if err == nil {
+				t.Errorf("Expected RegistrantRangeAssigned of `%s` to fail", c.isbn)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Failed RegistrantRangeAssigned of `%s`, error: %s", c.isbn, err)
+			continue
+		}
+		if assigned != c.assigned {
+			t.Errorf("RegistrantRangeAssigned of `%s` was %v, expected %v", c.isbn, assigned, c.assigned)
+		}
+	}
+}
+
+// withCheck13 replaces a trailing `?` in an ISBN-13 with its check digit
+func withCheck13(s string) string {
+	if !strings.HasSuffix(s, "?") {
+		return s
+	}
+	n := &ISBN{is13: true}
+	digits := strings.Map(runeToISBNDigit, s)
+	copy(n.prefix[:], digits[:3])
+	copy(n.digits[:], digits[3:12])
+	return s[:len(s)-1] + string(isbnDigitToByte(check13(n.prefix, n.digits)))
+}
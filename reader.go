@@ -0,0 +1,225 @@
+package isbn
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxLineLen is the most of a line the streaming readers look at.
+// Anything longer cannot be an ISBN, even with surrounding whitespace,
+// so the rest is skipped unread rather than buffered.
+const maxLineLen = 256
+
+// maxRowLen is the most of a row ReadTSV looks at. Rows hold more than
+// an ISBN, so it is more generous than maxLineLen, but longer rows are
+// still skipped unread.
+const maxRowLen = 16 * 1024
+
+// readBoundedLine reads the next line from br in chunks, keeping at
+// most max bytes of it in buf, so a giant line is never held in memory.
+// It reports whether the line was blank (only whitespace, however long)
+// or too long to keep. The error is io.EOF only when there is no line
+// left, a final line without a newline is returned as normal.
+func readBoundedLine(br *bufio.Reader, buf []byte, max int) (line []byte, blank, tooLong bool, err error) {
+	line = buf[:0]
+	blank = true
+	chunk, isPrefix, err := br.ReadLine()
+	if err != nil {
+		return nil, false, false, err
+	}
+	for {
+		if blank && len(bytes.TrimSpace(chunk)) > 0 {
+			blank = false
+		}
+		if tooLong || len(line)+len(chunk) > max {
+			tooLong = true
+		} else {
+			line = append(line, chunk...)
+		}
+		if !isPrefix {
+			return line, blank, tooLong, nil
+		}
+		chunk, isPrefix, err = br.ReadLine()
+		if err == io.EOF {
+			// a final line without a newline that filled the buffer
+			return line, blank, tooLong, nil
+		}
+		if err != nil {
+			return nil, false, false, err
+		}
+	}
+}
+
+// ValidateLines reads one ISBN per line from r and returns the
+// (1-based) line numbers of the lines that did not parse.
+// Blank lines are skipped, and both LF and CRLF endings are accepted.
+// The input is streamed, so it can be as large as you like, and lines
+// longer than maxLineLen are reported as invalid without being held in
+// memory.
+func ValidateLines(r io.Reader) ([]int, error) {
+	var invalid []int
+	br := bufio.NewReader(r)
+	buf := make([]byte, 0, maxLineLen)
+	for line := 1; ; line++ {
+		b, blank, tooLong, err := readBoundedLine(br, buf, maxLineLen)
+		if err == io.EOF {
+			return invalid, nil
+		}
+		if err != nil {
+			return invalid, err
+		}
+		if blank {
+			continue
+		}
+		if tooLong || !Validate(string(bytes.TrimSpace(b))) {
+			invalid = append(invalid, line)
+		}
+	}
+}
+
+// ReadTSV reads tab separated rows from r, finding the ISBN column by
+// the name isbnHeader in the header row (the first, if the name is
+// repeated). For every following row fn is called with the row keyed by
+// header (again keeping the first column of a repeated name), and the
+// parsed ISBN or the parse error; reading stops early if fn returns
+// false. Blank lines are skipped, and rows longer than maxRowLen are
+// not read, fn gets a nil row and an error for them. A missing header
+// row or ISBN column is returned as an error.
+func ReadTSV(r io.Reader, isbnHeader string, fn func(row map[string]string, isbn *ISBN, err error) bool) error {
+	br := bufio.NewReader(r)
+	buf := make([]byte, 0, maxRowLen)
+	b, _, tooLong, err := readBoundedLine(br, buf, maxRowLen)
+	if err == io.EOF {
+		return fmt.Errorf("TSV has no header row")
+	}
+	if err != nil {
+		return err
+	}
+	if tooLong {
+		return fmt.Errorf("TSV header row is longer than %d bytes", maxRowLen)
+	}
+	header := strings.Split(string(b), "\t")
+	col := -1
+	for i, h := range header {
+		if h == isbnHeader {
+			col = i
+			break
+		}
+	}
+	if col == -1 {
+		return fmt.Errorf("TSV has no %q column", isbnHeader)
+	}
+	for line := 2; ; line++ {
+		b, blank, tooLong, err := readBoundedLine(br, buf, maxRowLen)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if blank {
+			continue
+		}
+		if tooLong {
+			if !fn(nil, nil, fmt.Errorf("TSV row %d is longer than %d bytes", line, maxRowLen)) {
+				return nil
+			}
+			continue
+		}
+		cells := strings.Split(string(b), "\t")
+		row := make(map[string]string, len(header))
+		for i, h := range header {
+			if _, ok := row[h]; ok {
+				// a repeated name keeps its first column, like col
+				continue
+			}
+			if i < len(cells) {
+				row[h] = cells[i]
+			} else {
+				row[h] = ""
+			}
+		}
+		cell := ""
+		if col < len(cells) {
+			cell = cells[col]
+		}
+		n, err := Parse(strings.TrimSpace(cell))
+		if !fn(row, n, err) {
+			return nil
+		}
+	}
+}
+
+// maxCSVLineLen is the longest line ReadExcelCSV reads. encoding/csv
+// keeps a whole line while splitting it, so it is smaller than
+// maxRowLen.
+const maxCSVLineLen = 8 * 1024
+
+// utf8BOM is the byte order mark Excel starts UTF-8 CSV exports with
+var utf8BOM = []byte("\ufeff")
+
+// ReadExcelCSV reads comma separated rows as Excel exports them,
+// calling fn with the ISBN parsed from column col (0-based) of every
+// row, or the parse error; reading stops early if fn returns false.
+// Any header row is passed to fn like the others. The quirks handled
+// are a leading UTF-8 byte order mark, stray quotes, and the formula
+// form `="0836220889"` used to keep leading zeros. A row too short to
+// have the column gets a parse error, while a malformed CSV, or a line
+// longer than maxCSVLineLen, stops reading and is returned as an error
+// without the rest of the line being held in memory.
+func ReadExcelCSV(r io.Reader, col int, fn func(isbn *ISBN, err error) bool) error {
+	br := bufio.NewReader(r)
+	if b, err := br.Peek(len(utf8BOM)); err == nil && bytes.Equal(b, utf8BOM) {
+		br.Discard(len(utf8BOM))
+	}
+	cr := csv.NewReader(&lineLimitReader{r: br, max: maxCSVLineLen})
+	cr.LazyQuotes = true
+	cr.FieldsPerRecord = -1
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		cell := ""
+		if col >= 0 && col < len(record) {
+			cell = strings.TrimSpace(record[col])
+		}
+		if strings.HasPrefix(cell, `="`) && strings.HasSuffix(cell, `"`) {
+			cell = cell[1:]
+		}
+		n, err := Parse(strings.Trim(cell, `"`))
+		if !fn(n, err) {
+			return nil
+		}
+	}
+}
+
+// lineLimitReader fails once a line is longer than max bytes, to bound
+// how much of it encoding/csv buffers.
+type lineLimitReader struct {
+	r        io.Reader
+	max      int
+	line, at int
+}
+
+func (l *lineLimitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	for i, b := range p[:n] {
+		if b == '\n' {
+			l.line, l.at = l.line+1, 0
+			continue
+		}
+		l.at++
+		if l.at > l.max {
+			return i, fmt.Errorf("CSV line %d is longer than %d bytes", l.line+1, l.max)
+		}
+	}
+	return n, err
+}
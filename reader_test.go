@@ -0,0 +1,174 @@
+package isbn
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateLines(t *testing.T) {
+	cases := []struct {
+		input   string
+		invalid []int
+	}{
+		{"", nil},
+		{"0836220889\n9780836220889\n", nil},
+		{"0836220889\nbadformat!\n\n9780836220880\n", []int{2, 4}},
+		// CRLF line endings
+		{"0836220889\r\nbadformat!\r\n\r\n9780836218251\r\n", []int{2}},
+		// trailing line without a newline
+		{"0836220889\n08362208891", []int{2}},
+		{"badformat!\n0836220889", []int{1}},
+		// a trailing line filling the read buffer exactly
+		{"0836220889\n" + strings.Repeat("9", 4095), []int{2}},
+		{"0836220889\n" + strings.Repeat("9", 4096), []int{2}},
+		{"0836220889\n" + strings.Repeat("9", 4097), []int{2}},
+		{"0836220889\n" + strings.Repeat("9", 8192), []int{2}},
+	}
+	for _, c := range cases {
+		invalid, err := ValidateLines(strings.NewReader(c.input))
+		if err != nil {
+			t.Errorf("ValidateLines(%.20q...) failed: %s", c.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(invalid, c.invalid) {
+			t.Errorf("ValidateLines(%.20q...) = %v, expected %v", c.input, invalid, c.invalid)
+		}
+	}
+}
+
+func TestReadTSV(t *testing.T) {
+	input := "title\tISBN\tyear\r\n" +
+		"Calvin and Hobbes\t0836220889\t1987\r\n" +
+		"\r\n" +
+		"  \t \r\n" +
+		"Bad\tbadformat!\t\r\n" +
+		"Short row\r\n" +
+		"Yukon Ho!\t978-0836218350\t1989"
+	type row struct {
+		title, isbn string
+		valid       bool
+	}
+	var rows []row
+	err := ReadTSV(strings.NewReader(input), "ISBN", func(r map[string]string, n *ISBN, err error) bool {
+		if len(r) != 3 {
+			t.Errorf("Expected all columns in the row, got %v", r)
+		}
+		s := ""
+		if n != nil {
+			s = n.String()
+		}
+		rows = append(rows, row{r["title"], s, err == nil})
+		return true
+	})
+	if err != nil {
+		t.Fatalf("ReadTSV failed: %s", err)
+	}
+	expected := []row{
+		{"Calvin and Hobbes", "0836220889", true},
+		{"Bad", "", false},
+		{"Short row", "", false},
+		{"Yukon Ho!", "978-0836218350", true},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("ReadTSV rows were %v, expected %v", rows, expected)
+	}
+
+	// stopping early
+	count := 0
+	ReadTSV(strings.NewReader(input), "ISBN", func(map[string]string, *ISBN, error) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("Expected ReadTSV to stop after 1 row, got %d", count)
+	}
+
+	// a repeated header name finds the first column
+	var found []string
+	ReadTSV(strings.NewReader("ISBN\tISBN\n0836220889\tbadformat!\n"), "ISBN", func(r map[string]string, n *ISBN, err error) bool {
+		checkStringEqual(t, "Repeated header cell", r["ISBN"], "0836220889")
+		if err != nil {
+			t.Errorf("Expected the first ISBN column to be parsed, got %s", err)
+		} else {
+			found = append(found, n.String())
+		}
+		return true
+	})
+	if !reflect.DeepEqual(found, []string{"0836220889"}) {
+		t.Errorf("ReadTSV with a repeated header gave %v", found)
+	}
+
+	// an oversized row is reported, and reading carries on
+	long := "title\tISBN\n" + strings.Repeat("x", maxRowLen) + "\t0836220889\nCalvin and Hobbes\t0836220889"
+	var rowErrs []error
+	err = ReadTSV(strings.NewReader(long), "ISBN", func(r map[string]string, n *ISBN, err error) bool {
+		rowErrs = append(rowErrs, err)
+		return true
+	})
+	if err != nil || len(rowErrs) != 2 || rowErrs[0] == nil || rowErrs[1] != nil {
+		t.Errorf("Expected an error for the long row then an ISBN, got %v, %v", rowErrs, err)
+	}
+
+	for _, bad := range []string{"", "title\tyear\nfoo\t1987\n"} {
+		err := ReadTSV(strings.NewReader(bad), "ISBN", func(map[string]string, *ISBN, error) bool {
+			t.Errorf("Expected no rows for `%s`", bad)
+			return true
+		})
+		if err == nil {
+			t.Errorf("Expected ReadTSV(%q) to fail", bad)
+		}
+	}
+}
+
+func TestReadExcelCSV(t *testing.T) {
+	input := "\ufeffISBN,title\r\n" +
+		"0836220889,Calvin and Hobbes\r\n" +
+		"=\"0836218256\",Something Under the Bed Is Drooling\r\n" +
+		"\"=\"\"0836218353\"\"\",Yukon Ho!\r\n" +
+		"\"978-1449407100\",\"Weirdos from Another Planet!\"\r\n" +
+		"\r\n" +
+		"badformat!,Bad\r\n" +
+		"\r\n"
+	var isbns []string
+	err := ReadExcelCSV(strings.NewReader(input), 0, func(n *ISBN, err error) bool {
+		if err != nil {
+			isbns = append(isbns, "")
+		} else {
+			isbns = append(isbns, n.String())
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatalf("ReadExcelCSV failed: %s", err)
+	}
+	// the header is not an ISBN
+	expected := []string{"", "0836220889", "0836218256", "0836218353", "978-1449407100", ""}
+	if !reflect.DeepEqual(isbns, expected) {
+		t.Errorf("ReadExcelCSV ISBNs were %q, expected %q", isbns, expected)
+	}
+
+	// a formula straight after the byte order mark, and a short row
+	var errs []error
+	ReadExcelCSV(strings.NewReader("\ufeff=\"0836220889\",x\n=\"0836220889\"\n"), 1, func(n *ISBN, err error) bool {
+		errs = append(errs, err)
+		return true
+	})
+	ReadExcelCSV(strings.NewReader("\ufeff=\"0836220889\"\n"), 0, func(n *ISBN, err error) bool {
+		errs = append(errs, err)
+		return true
+	})
+	if len(errs) != 3 || errs[0] == nil || errs[1] == nil || errs[2] != nil {
+		t.Errorf("Expected two parse errors then an ISBN, got %v", errs)
+	}
+
+	// stopping early
+	count := 0
+	ReadExcelCSV(strings.NewReader(input), 0, func(*ISBN, error) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("Expected ReadExcelCSV to stop after 1 row, got %d", count)
+	}
+}
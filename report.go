@@ -0,0 +1,72 @@
+package isbn
+
+import (
+	"bytes"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Result is the outcome of parsing one input, see ParseAll
+type Result struct {
+	Input string
+	ISBN  *ISBN
+	Err   error
+}
+
+// ParseAll parses each of the inputs, keeping the inputs, ISBNs and
+// errors together in order.
+func ParseAll(inputs []string) []Result {
+	results := make([]Result, len(inputs))
+	for i, s := range inputs {
+		n, err := Parse(s)
+		results[i] = Result{Input: s, ISBN: n, Err: err}
+	}
+	return results
+}
+
+// maxReportInput is the widest input FormatReport shows untruncated
+const maxReportInput = 32
+
+// FormatReport lays out results as an aligned text table of the input,
+// the canonical form (blank when invalid) and the verdict, for terminal
+// output. Long inputs are truncated with an ellipsis and control
+// characters are replaced with spaces so they cannot break the layout.
+func FormatReport(results []Result) string {
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	w.Write([]byte("INPUT\tCANONICAL\tVERDICT\n"))
+	for _, r := range results {
+		canonical, verdict := "", "ok"
+		switch {
+		case r.Err != nil:
+			verdict = r.Err.Error()
+		case r.ISBN == nil:
+			verdict = "no ISBN"
+		default:
+			canonical = r.ISBN.Canonical()
+		}
+		w.Write([]byte(reportCell(r.Input) + "\t" + canonical + "\t" + reportCell(verdict) + "\n"))
+	}
+	w.Flush()
+	return b.String()
+}
+
+// reportCell makes s safe for a table cell, truncating long inputs
+func reportCell(s string) string {
+	if len(s) > maxReportInput*utf8.UTFMax {
+		// it will be truncated anyway, so don't map all of it
+		s = s[:maxReportInput*utf8.UTFMax]
+	}
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s)
+	if utf8.RuneCountInString(s) > maxReportInput {
+		s = string([]rune(s)[:maxReportInput-1]) + "…"
+	}
+	return s
+}
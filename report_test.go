@@ -0,0 +1,32 @@
+package isbn
+
+import (
+	"testing"
+)
+
+func TestParseAll(t *testing.T) {
+	results := ParseAll([]string{"080442957X", "badformat!"})
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Err != nil || results[0].ISBN == nil || results[0].Input != "080442957X" {
+		t.Errorf("Unexpected result for a valid ISBN: %+v", results[0])
+	}
+	if results[1].Err == nil || results[1].ISBN != nil {
+		t.Errorf("Unexpected result for an invalid ISBN: %+v", results[1])
+	}
+}
+
+func TestFormatReport(t *testing.T) {
+	results := ParseAll([]string{
+		"080442957X",
+		"0836220888",
+		"a very long input that goes on and on and on\tand has a tab",
+	})
+	expected := "" +
+		"INPUT                             CANONICAL                VERDICT\n" +
+		"080442957X                        urn:isbn:978-0804429573  ok\n" +
+		"0836220888                                                 ISBN checksum was incorrect\n" +
+		"a very long input that goes on …                           Invalid ISBN format\n"
+	checkStringEqual(t, "Report should match", FormatReport(results), expected)
+}
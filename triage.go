@@ -0,0 +1,75 @@
+package isbn
+
+import (
+	"strings"
+)
+
+// Severity ranks how badly an input failed to parse, see Triage
+type Severity int
+
+const (
+	// SeverityNone means the input is a valid ISBN
+	SeverityNone Severity = iota
+	// SeverityAutoFixable means only whitespace or letter case is wrong,
+	// so the suggestion can be applied without review
+	SeverityAutoFixable
+	// SeverityLikelyTypo means the format is right but the checksum is
+	// not, so the suggestion (from MinimalFix) needs human review
+	SeverityLikelyTypo
+	// SeverityReject means the input is not recognisably an ISBN
+	SeverityReject
+)
+
+// Triage classifies an input for a cleanup queue, returning its
+// severity, a suggested ISBN where there is one, and the error from
+// Parse (nil for SeverityNone, when the suggestion is the ISBN itself).
+// Inputs with too much whitespace, or an upper case urn prefix, are
+// auto-fixable; inputs that fail only the checksum, after that clean
+// up, are likely typos and get the MinimalFix suggestion if one exists;
+// everything else is rejected.
+func Triage(s string) (level Severity, suggestion *ISBN, err error) {
+	n, err := Parse(s)
+	if err == nil {
+		return SeverityNone, n, nil
+	}
+	if len(s) > maxLineLen {
+		return SeverityReject, nil, err
+	}
+	cleaned := strings.Join(strings.Fields(s), "")
+	if len(cleaned) >= len(urnPrefix) && strings.EqualFold(cleaned[:len(urnPrefix)], urnPrefix) {
+		cleaned = urnPrefix + cleaned[len(urnPrefix):]
+	}
+	if n, cerr := Parse(cleaned); cerr == nil {
+		return SeverityAutoFixable, n, err
+	}
+	if parseDigits(cleaned, &ISBN{}) == nil {
+		fixed, _, _ := MinimalFix(cleaned)
+		return SeverityLikelyTypo, fixed, err
+	}
+	return SeverityReject, nil, err
+}
+
+// PeekPrefix returns the apparent GS1 prefix of s without validating
+// it, for bucketing bad records. The urn prefix and anything that is
+// not a digit are stripped, and if exactly 13 digits remain the first
+// three are returned; otherwise it returns false. Neither the prefix
+// nor the checksum are checked.
+func PeekPrefix(s string) (string, bool) {
+	s = strings.TrimPrefix(s, urnPrefix)
+	var digits [13]byte
+	count := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			continue
+		}
+		if count == len(digits) {
+			return "", false
+		}
+		digits[count] = s[i]
+		count++
+	}
+	if count != len(digits) {
+		return "", false
+	}
+	return string(digits[:3]), true
+}
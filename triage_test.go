@@ -0,0 +1,67 @@
+package isbn
+
+import (
+	"testing"
+)
+
+func TestPeekPrefix(t *testing.T) {
+	cases := []struct {
+		input  string
+		prefix string
+		ok     bool
+	}{
+		{"9780804429573", "978", true},
+		// checksum and prefix are not checked
+		{"9780804429574", "978", true},
+		{"977-0804429573", "977", true},
+		{"urn:isbn:979 50 00 00 02 35", "979", true},
+		// not 13 digits
+		{"080442957X", "", false},
+		{"978080442957", "", false},
+		{"97808044295731", "", false},
+		{"", "", false},
+	}
+	for _, c := range cases {
+		prefix, ok := PeekPrefix(c.input)
+		if prefix != c.prefix || ok != c.ok {
+			t.Errorf("PeekPrefix(%s) = %s, %v, expected %s, %v", c.input, prefix, ok, c.prefix, c.ok)
+		}
+	}
+}
+
+func TestTriage(t *testing.T) {
+	cases := []struct {
+		input      string
+		level      Severity
+		suggestion string
+	}{
+		{"080442957X", SeverityNone, "080442957X"},
+		{"urn:isbn:978-0-8044-2957-3", SeverityNone, "978-0804429573"},
+		// whitespace and case
+		{"   9 7 8 0 8 0 4 4 2 9 5 7 3  ", SeverityAutoFixable, "978-0804429573"},
+		{"URN:ISBN:9780804429573", SeverityAutoFixable, "978-0804429573"},
+		{"Urn:Isbn: 080442957X\t", SeverityAutoFixable, "080442957X"},
+		// checksums
+		{"0836220888", SeverityLikelyTypo, "0836220889"},
+		{"080424957X", SeverityLikelyTypo, "080442957X"},
+		{" 978 0 8044 2957 4 ", SeverityLikelyTypo, "978-0804429573"},
+		// garbage
+		{"badformat!", SeverityReject, ""},
+		{"08362208891", SeverityReject, ""},
+		{"", SeverityReject, ""},
+	}
+	for _, c := range cases {
+		level, suggestion, err := Triage(c.input)
+		if level != c.level {
+			t.Errorf("Triage(%q) severity was %d, expected %d", c.input, level, c.level)
+		}
+		if (err == nil) != (level == SeverityNone) {
+			t.Errorf("Triage(%q) error was %v for severity %d", c.input, err, level)
+		}
+		s := ""
+		if suggestion != nil {
+			s = suggestion.String()
+		}
+		checkStringEqual(t, "Triage suggestion for "+c.input, s, c.suggestion)
+	}
+}
@@ -0,0 +1,55 @@
+package isbn
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// WebForms returns the web facing identifiers for the ISBN in one go:
+// the canonical form, the ISBN-13 urn, the GS1 Digital Link URI under
+// the resolver `base` (e.g. `https://id.gs1.org`) and the ISBN-A DOI.
+func (n *ISBN) WebForms(base string) (canonical, urn, digitalLink, isbnA string, err error) {
+	digitalLink, err = n.digitalLink(base)
+	if err != nil {
+		return "", "", "", "", err
+	}
+	isbnA, err = n.isbnA()
+	if err != nil {
+		return "", "", "", "", err
+	}
+	return n.Canonical(), n.To13().ToURN(), digitalLink, isbnA, nil
+}
+
+// digitalLink gives the GS1 Digital Link URI, which identifies the
+// book by its GTIN-14 (the ISBN-13 with a leading zero). The base may
+// have a path, but not a query or fragment.
+func (n *ISBN) digitalLink(base string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("Digital Link base must be an absolute URL: %s", base)
+	}
+	if u.RawQuery != "" || u.ForceQuery || u.Fragment != "" {
+		// the path is appended, so it must be the end of the base
+		return "", fmt.Errorf("Digital Link base must not have a query or fragment: %s", base)
+	}
+	return strings.TrimSuffix(base, "/") + "/01/0" + n.to13Digits(), nil
+}
+
+// isbnA gives the ISBN-A DOI, e.g. `10.978.08044/29573`, which needs
+// the range data to find the registrant.
+func (n *ISBN) isbnA() (string, error) {
+	seg, err := n.To13().segments()
+	if err != nil {
+		return "", err
+	}
+	return "10." + seg.prefix + "." + seg.group + seg.registrant + "/" + seg.publication + seg.check, nil
+}
+
+// to13Digits returns the 13 digits of the ISBN-13 form, no hyphens
+func (n *ISBN) to13Digits() string {
+	return string(n.To13().appendDigits(make([]byte, 0, 13), ""))
+}
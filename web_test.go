@@ -0,0 +1,41 @@
+package isbn
+
+import (
+	"testing"
+)
+
+func TestWebForms(t *testing.T) {
+	n, err := Parse("080442957X")
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	canonical, urn, link, doi, err := n.WebForms("https://id.gs1.org/")
+	if err != nil {
+		t.Fatalf("WebForms failed: %s", err)
+	}
+	checkStringEqual(t, "Canonical form", canonical, "urn:isbn:978-0804429573")
+	checkStringEqual(t, "URN form", urn, "urn:isbn:978-0804429573")
+	checkStringEqual(t, "Digital Link", link, "https://id.gs1.org/01/09780804429573")
+	checkStringEqual(t, "ISBN-A", doi, "10.978.08044/29573")
+
+	if _, _, _, _, err := n.WebForms("id.gs1.org"); err == nil {
+		t.Errorf("Expected a relative Digital Link base to fail")
+	}
+	for _, base := range []string{"https://example.com/x?y=1", "https://example.com/x?", "https://example.com/x#y"} {
+		if _, _, link, _, err := n.WebForms(base); err == nil {
+			t.Errorf("Expected Digital Link base %s to fail, got %s", base, link)
+		}
+	}
+	_, _, link, _, err = n.WebForms("https://example.com/resolver/")
+	if err != nil {
+		t.Fatalf("WebForms failed with a base path: %s", err)
+	}
+	checkStringEqual(t, "Digital Link under a path", link, "https://example.com/resolver/01/09780804429573")
+	n, err = Parse(test979isbn)
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	if _, _, _, _, err := n.WebForms("https://id.gs1.org"); err == nil {
+		t.Errorf("Expected WebForms to fail for an unresolvable range")
+	}
+}